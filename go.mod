@@ -1,17 +1,19 @@
 module github.com/go-gilbert/gilbert
 
-go 1.22
+go 1.22.0
 
 toolchain go1.23.4
 
 require (
 	github.com/axw/gocov v0.0.0-20170322000131-3a69a0d2a4ef
+	github.com/expr-lang/expr v1.16.9
 	github.com/fatih/color v1.7.0
 	github.com/go-viper/mapstructure/v2 v2.2.1
 	github.com/goccy/go-yaml v1.15.15
 	github.com/google/go-github/v25 v25.0.2
 	github.com/rjeczalik/notify v0.9.3
 	github.com/stretchr/testify v1.10.0
+	github.com/tetratelabs/wazero v1.9.0
 	github.com/urfave/cli v1.20.0
 	go.uber.org/mock v0.5.0
 	golang.org/x/oauth2 v0.0.0-20190402181905-9f3314589c9a
@@ -21,7 +23,6 @@ require (
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
-	github.com/expr-lang/expr v1.16.9 // indirect
 	github.com/golang/protobuf v1.2.0 // indirect
 	github.com/google/go-querystring v1.0.0 // indirect
 	github.com/mattn/go-colorable v0.0.9 // indirect
@@ -7,6 +7,7 @@ import (
 	"github.com/go-gilbert/gilbert/internal/actions/build"
 	"github.com/go-gilbert/gilbert/internal/actions/cover"
 	"github.com/go-gilbert/gilbert/internal/actions/cover/html"
+	"github.com/go-gilbert/gilbert/internal/actions/gotest"
 	"github.com/go-gilbert/gilbert/internal/actions/pkgget"
 	"github.com/go-gilbert/gilbert/internal/actions/shell"
 	"github.com/go-gilbert/gilbert/internal/actions/watch"
@@ -21,4 +22,5 @@ var BuiltinHandlers = runner.ActionHandlers{
 	"watch":       watch.NewAction,
 	"cover":       cover.NewAction,
 	"cover:html":  html.NewAction,
+	"test":        gotest.NewAction,
 }
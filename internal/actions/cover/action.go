@@ -1,17 +1,25 @@
 package cover
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
+	"github.com/axw/gocov/gocovutil"
+	"github.com/go-gilbert/gilbert/internal/actions/cover/badge"
+	"github.com/go-gilbert/gilbert/internal/actions/cover/diff"
+	"github.com/go-gilbert/gilbert/internal/actions/cover/history"
 	"github.com/go-gilbert/gilbert/internal/actions/cover/profile"
 	"github.com/go-gilbert/gilbert/internal/actions/cover/report"
 	"github.com/go-gilbert/gilbert/internal/log"
 	"github.com/go-gilbert/gilbert/internal/runner"
 	"github.com/go-gilbert/gilbert/internal/runner/job"
 	"github.com/go-gilbert/gilbert/internal/scope"
+	"github.com/go-gilbert/gilbert/internal/support/git"
 	"github.com/go-gilbert/gilbert/internal/support/shell"
 )
 
@@ -20,6 +28,7 @@ type Action struct {
 	scope     *scope.Scope
 	params    params
 	coverFile *os.File
+	tempFiles []string
 	alive     bool
 }
 
@@ -50,7 +59,12 @@ func (a *Action) Call(ctx *job.RunContext, _ *runner.TaskRunner) (err error) {
 		return nil
 	}
 
-	pkgs, err := profile.ConvertProfiles(a.coverFile.Name())
+	extra, err := a.extraProfiles(ctx)
+	if err != nil {
+		return err
+	}
+
+	pkgs, err := profile.ConvertProfiles(append([]string{a.coverFile.Name()}, extra...)...)
 	if err != nil {
 		return fmt.Errorf("failed to parse cover profile file, %s", err)
 	}
@@ -62,16 +76,127 @@ func (a *Action) Call(ctx *job.RunContext, _ *runner.TaskRunner) (err error) {
 
 	// Check coverage
 	a.printUncoveredItems(ctx.Log(), repFmt)
-	prof := profile.Create(*pkgs)
-	err = prof.CheckCoverage(a.params.Threshold)
+	filtered := profile.Exclude(*pkgs, a.params.Exclude)
+	prof := profile.Create(filtered)
+	if err = a.exportReport(ctx, &prof); err != nil {
+		return err
+	}
+
+	if a.params.Badge.Path != "" {
+		if err = a.writeBadge(ctx, &prof); err != nil {
+			return err
+		}
+	}
+
+	var dropErr error
+	if a.params.RecordHistory {
+		cur, prev, err := a.recordHistory(ctx, &prof)
+		if err != nil {
+			return err
+		}
+
+		if prev != nil && a.params.MaxDrop >= 0 {
+			dropErr = history.CheckDrop(*prev, cur, a.params.MaxDrop)
+		}
+	}
+
+	// In diff mode, threshold is applied only to changed statements
+	thresholdErr := prof.CheckCoverage(a.params.Threshold)
+	if a.params.DiffAgainst != "" {
+		r, err := a.createDiffReport(ctx, filtered)
+		if err != nil {
+			return err
+		}
+
+		thresholdErr = r.CheckCoverage(a.params.Threshold)
+	}
+
+	err = errors.Join(thresholdErr, prof.CheckPackagesCoverage(a.params.Thresholds), dropErr)
 	if err != nil || a.params.Report {
 		a.printReport(ctx, &prof)
-		return err
+	}
+
+	if threshold, ok, _ := a.params.functionsThreshold(); ok {
+		a.printFunctions(ctx, &prof, threshold)
 	}
 
 	return err
 }
 
+// printFunctions prints functions with coverage below threshold and their uncovered lines
+func (a *Action) printFunctions(ctx *job.RunContext, r *profile.Report, threshold float64) {
+	str := r.FormatFunctions(a.scope.Environment().ProjectDirectory, threshold)
+	if str == "" {
+		ctx.Log().Infof("No functions with coverage below %.2f%%", threshold)
+		return
+	}
+
+	ctx.Log().Warnf("Functions with coverage below %.2f%%:", threshold)
+	_, _ = ctx.Log().Write([]byte(str))
+}
+
+// createDiffReport creates and prints coverage report of statements changed since base revision
+func (a *Action) createDiffReport(ctx *job.RunContext, pkgs gocovutil.Packages) (*profile.DiffReport, error) {
+	base, err := a.scope.ExpandVariables(a.params.DiffAgainst)
+	if err != nil {
+		return nil, err
+	}
+
+	projectDir := a.scope.Environment().ProjectDirectory
+	changes, err := diff.ChangedLines(ctx.Context(), projectDir, base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get changes since '%s', %s", base, err)
+	}
+
+	r := profile.CreateDiff(pkgs, changes)
+	if r.Total == 0 {
+		ctx.Log().Infof("No changed statements since '%s'", base)
+		return &r, nil
+	}
+
+	ctx.Log().Infof("Changed statements coverage: %.2f%% (%d of %d statements since '%s')", r.Percentage(), r.Reached, r.Total, base)
+	if len(r.Uncovered) > 0 {
+		ctx.Log().Warn("Uncovered changed lines:")
+		_, _ = ctx.Log().Write([]byte(r.FormatUncovered(projectDir)))
+	}
+
+	return &r, nil
+}
+
+// recordHistory saves report totals of the current commit to coverage history.
+//
+// Returns recorded entry and the latest entry of another commit, if any.
+func (a *Action) recordHistory(ctx *job.RunContext, r *profile.Report) (history.Entry, *history.Entry, error) {
+	projectDir := a.scope.Environment().ProjectDirectory
+	commit, err := git.HeadCommit(ctx.Context(), projectDir)
+	if err != nil {
+		return history.Entry{}, nil, fmt.Errorf("failed to get current commit for coverage history, %s", err)
+	}
+
+	fileName, err := history.Path(projectDir)
+	if err != nil {
+		return history.Entry{}, nil, err
+	}
+
+	entries, err := history.Load(fileName)
+	if err != nil {
+		return history.Entry{}, nil, err
+	}
+
+	entry := history.NewEntry(commit, r)
+	if err := history.Record(fileName, entry); err != nil {
+		return history.Entry{}, nil, fmt.Errorf("failed to record coverage history, %s", err)
+	}
+
+	ctx.Log().Debugf("cover: coverage of commit %s saved to '%s'", commit, fileName)
+	prev, ok := history.Previous(entries, commit)
+	if !ok {
+		return entry, nil, nil
+	}
+
+	return entry, &prev, nil
+}
+
 func (a *Action) printUncoveredItems(l log.Logger, fpFmt *report.Formatter) {
 	uncovered, count := fpFmt.UncoveredPackages()
 	if count == 0 {
@@ -121,19 +246,88 @@ func (a *Action) printReport(ctx *job.RunContext, r *profile.Report) {
 	ctx.Log().Infof("Total coverage: %.2f%%", r.Percentage())
 }
 
+func (a *Action) exportReport(ctx *job.RunContext, r *profile.Report) error {
+	projectDir := a.scope.Environment().ProjectDirectory
+	for _, o := range a.params.Output {
+		fileName, err := a.scope.ExpandVariables(o.Path)
+		if err != nil {
+			return err
+		}
+
+		if !filepath.IsAbs(fileName) {
+			fileName = filepath.Join(projectDir, fileName)
+		}
+
+		if err := writeReportFile(fileName, o.Format, projectDir, r); err != nil {
+			return fmt.Errorf("failed to write %s coverage report, %s", o.Format, err)
+		}
+
+		ctx.Log().Debugf("cover: %s report saved to '%s'", o.Format, fileName)
+	}
+
+	return nil
+}
+
+// writeBadge renders SVG badge with total coverage
+func (a *Action) writeBadge(ctx *job.RunContext, r *profile.Report) error {
+	if err := a.scope.Scan(&a.params.Badge.Path, &a.params.Badge.Label); err != nil {
+		return err
+	}
+
+	fileName := a.params.Badge.Path
+	if !filepath.IsAbs(fileName) {
+		fileName = filepath.Join(a.scope.Environment().ProjectDirectory, fileName)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+		return fmt.Errorf("failed to create coverage badge directory, %s", err)
+	}
+
+	buff := &bytes.Buffer{}
+	if err := badge.Render(buff, a.params.Badge.Label, r.Percentage(), a.params.Badge.Bands); err != nil {
+		return fmt.Errorf("failed to render coverage badge, %s", err)
+	}
+
+	if err := os.WriteFile(fileName, buff.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write coverage badge, %s", err)
+	}
+
+	ctx.Log().Debugf("cover: coverage badge saved to '%s'", fileName)
+	return nil
+}
+
+func writeReportFile(fileName, format, sourceDir string, r *profile.Report) error {
+	if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+
+	if err := r.Export(f, format, sourceDir); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
 func (a *Action) clean(ctx *job.RunContext) {
 	if !a.alive {
 		return
 	}
 
 	a.alive = false
-	fname := a.coverFile.Name()
-	if err := os.Remove(fname); err != nil {
-		ctx.Log().Debugf("cover: failed to remove cover file '%s': %s", fname, err)
-		return
-	}
+	for _, fname := range append([]string{a.coverFile.Name()}, a.tempFiles...) {
+		if err := os.Remove(fname); err != nil {
+			ctx.Log().Debugf("cover: failed to remove cover file '%s': %s", fname, err)
+			continue
+		}
 
-	ctx.Log().Debugf("cover: removed cover file '%s'", fname)
+		ctx.Log().Debugf("cover: removed cover file '%s'", fname)
+	}
 }
 
 func (a *Action) createCoverCommand(ctx *job.RunContext) (*exec.Cmd, error) {
@@ -0,0 +1,129 @@
+/*
+Package badge renders SVG code coverage badges
+*/
+package badge
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"sort"
+	"text/template"
+)
+
+// DefaultLabel is default badge label text
+const DefaultLabel = "coverage"
+
+const (
+	// charWidth is approximate width of a single character in pixels (11px Verdana)
+	charWidth = 7
+
+	// padding is horizontal text padding in pixels
+	padding = 10
+)
+
+// Band is badge color used when coverage is greater or equal to Min
+type Band struct {
+	Min   float64 `mapstructure:"min"`
+	Color string  `mapstructure:"color"`
+}
+
+// Bands is list of badge color bands
+type Bands []Band
+
+// DefaultBands are color bands used when no bands specified
+var DefaultBands = Bands{
+	{Min: 80, Color: "#4c1"},
+	{Min: 60, Color: "#dfb317"},
+	{Min: 0, Color: "#e05d44"},
+}
+
+// Validate checks color bands
+func (b Bands) Validate() error {
+	for _, band := range b {
+		if band.Color == "" {
+			return fmt.Errorf("missing color of badge band with min coverage %.2f", band.Min)
+		}
+
+		if band.Min > 100 || band.Min < 0 {
+			return fmt.Errorf("min coverage of '%s' badge band should be between 0 and 100 (got %f)", band.Color, band.Min)
+		}
+	}
+
+	return nil
+}
+
+// Color returns color of band with highest min value that fits coverage.
+//
+// Color of the lowest band is used if coverage is below all bands.
+func (b Bands) Color(percentage float64) string {
+	if len(b) == 0 {
+		b = DefaultBands
+	}
+
+	sorted := make(Bands, len(b))
+	copy(sorted, b)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Min > sorted[j].Min
+	})
+
+	for _, band := range sorted {
+		if percentage >= band.Min {
+			return band.Color
+		}
+	}
+
+	return sorted[len(sorted)-1].Color
+}
+
+var badgeTemplate = template.Must(template.New("badge").Funcs(template.FuncMap{
+	"escape": html.EscapeString,
+}).Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="{{.Width}}" height="20" role="img" aria-label="{{escape .Label}}: {{escape .Value}}">
+<title>{{escape .Label}}: {{escape .Value}}</title>
+<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>
+<clipPath id="r"><rect width="{{.Width}}" height="20" rx="3" fill="#fff"/></clipPath>
+<g clip-path="url(#r)">
+<rect width="{{.LabelWidth}}" height="20" fill="#555"/>
+<rect x="{{.LabelWidth}}" width="{{.ValueWidth}}" height="20" fill="{{escape .Color}}"/>
+<rect width="{{.Width}}" height="20" fill="url(#s)"/>
+</g>
+<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+<text x="{{.LabelX}}" y="15" fill="#010101" fill-opacity=".3">{{escape .Label}}</text>
+<text x="{{.LabelX}}" y="14">{{escape .Label}}</text>
+<text x="{{.ValueX}}" y="15" fill="#010101" fill-opacity=".3">{{escape .Value}}</text>
+<text x="{{.ValueX}}" y="14">{{escape .Value}}</text>
+</g>
+</svg>
+`))
+
+type badgeData struct {
+	Label, Value, Color    string
+	Width                  int
+	LabelWidth, ValueWidth int
+	LabelX, ValueX         float64
+}
+
+// Render writes SVG badge with coverage percentage
+func Render(w io.Writer, label string, percentage float64, bands Bands) error {
+	if label == "" {
+		label = DefaultLabel
+	}
+
+	value := fmt.Sprintf("%.1f%%", percentage)
+	d := badgeData{
+		Label:      label,
+		Value:      value,
+		Color:      bands.Color(percentage),
+		LabelWidth: textWidth(label),
+		ValueWidth: textWidth(value),
+	}
+
+	d.Width = d.LabelWidth + d.ValueWidth
+	d.LabelX = float64(d.LabelWidth) / 2
+	d.ValueX = float64(d.LabelWidth) + float64(d.ValueWidth)/2
+	return badgeTemplate.Execute(w, d)
+}
+
+func textWidth(s string) int {
+	return len([]rune(s))*charWidth + padding
+}
@@ -0,0 +1,57 @@
+package badge
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBands_Color(t *testing.T) {
+	bands := Bands{
+		{Min: 50, Color: "yellow"},
+		{Min: 90, Color: "green"},
+		{Min: 20, Color: "orange"},
+	}
+
+	cases := map[string]struct {
+		bands    Bands
+		value    float64
+		expected string
+	}{
+		"highest band":         {bands: bands, value: 95, expected: "green"},
+		"band min is included": {bands: bands, value: 50, expected: "yellow"},
+		"lowest band":          {bands: bands, value: 20.5, expected: "orange"},
+		"below all bands":      {bands: bands, value: 10, expected: "orange"},
+		"default bands":        {value: 70, expected: "#dfb317"},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			assert.Equal(t, c.expected, c.bands.Color(c.value))
+		})
+	}
+}
+
+func TestBands_Validate(t *testing.T) {
+	assert.NoError(t, DefaultBands.Validate())
+	assert.EqualError(t, Bands{{Min: 10}}.Validate(), "missing color of badge band with min coverage 10.00")
+	assert.EqualError(t, Bands{{Min: 110, Color: "red"}}.Validate(),
+		"min coverage of 'red' badge band should be between 0 and 100 (got 110.000000)")
+}
+
+func TestRender(t *testing.T) {
+	buff := &bytes.Buffer{}
+	require.NoError(t, Render(buff, "", 85.25, nil))
+
+	out := buff.String()
+	assert.Contains(t, out, `<svg xmlns="http://www.w3.org/2000/svg" width="111" height="20" role="img" aria-label="coverage: 85.2%">`)
+	assert.Contains(t, out, `<rect x="66" width="45" height="20" fill="#4c1"/>`)
+	assert.Contains(t, out, `<text x="88.5" y="14">85.2%</text>`)
+
+	buff.Reset()
+	require.NoError(t, Render(buff, "<tests>", 10, Bands{{Min: 0, Color: "red"}}))
+	assert.Contains(t, buff.String(), `<text x="29.5" y="14">&lt;tests&gt;</text>`)
+	assert.Contains(t, buff.String(), `fill="red"`)
+}
@@ -0,0 +1,93 @@
+package cover
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-gilbert/gilbert/internal/runner/job"
+	"github.com/go-gilbert/gilbert/internal/support/shell"
+)
+
+// extraProfiles returns additional cover profiles which should be merged with unit tests coverage.
+//
+// Binary coverage data from "coverDir" is converted to a cover profile.
+func (a *Action) extraProfiles(ctx *job.RunContext) ([]string, error) {
+	files := make([]string, 0, len(a.params.Profiles)+1)
+	for _, pattern := range a.params.Profiles {
+		val, err := a.expandPath(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		matches, err := filepath.Glob(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cover profile pattern '%s', %s", pattern, err)
+		}
+
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("cover profile '%s' not found", pattern)
+		}
+
+		files = append(files, matches...)
+	}
+
+	if a.params.CoverDir == "" {
+		return files, nil
+	}
+
+	dir, err := a.expandPath(a.params.CoverDir)
+	if err != nil {
+		return nil, err
+	}
+
+	converted, err := a.convertCoverDir(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(files, converted), nil
+}
+
+// convertCoverDir converts binary coverage data produced by "go build -cover" binaries to cover profile
+func (a *Action) convertCoverDir(ctx *job.RunContext, dir string) (string, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return "", fmt.Errorf("coverage data directory not found, %s", err)
+	}
+
+	f, err := os.CreateTemp(os.TempDir(), coverFilePattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to create coverage temporary file: %s", err)
+	}
+
+	_ = f.Close()
+	a.tempFiles = append(a.tempFiles, f.Name())
+
+	// go tool covdata textfmt -i=./coverdata -o=/tmp/cover.out
+	cmd := exec.CommandContext(ctx.Context(), "go", "tool", "covdata", "textfmt", "-i="+dir, "-o="+f.Name())
+	cmd.Dir = a.scope.Environment().ProjectDirectory
+	cmd.Stdout = ctx.Log()
+	cmd.Stderr = ctx.Log().ErrorWriter()
+	ctx.Log().Debugf("cover: exec '%s'", strings.Join(cmd.Args, " "))
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to convert coverage data from '%s' (%s)", dir, shell.FormatExitError(err))
+	}
+
+	return f.Name(), nil
+}
+
+// expandPath expands variables in path and resolves it relative to project directory
+func (a *Action) expandPath(p string) (string, error) {
+	val, err := a.scope.ExpandVariables(p)
+	if err != nil {
+		return "", err
+	}
+
+	if !filepath.IsAbs(val) {
+		val = filepath.Join(a.scope.Environment().ProjectDirectory, val)
+	}
+
+	return val, nil
+}
@@ -0,0 +1,108 @@
+package cover
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-gilbert/gilbert/internal/manifest/expr"
+	"github.com/go-gilbert/gilbert/internal/runner/job"
+	"github.com/go-gilbert/gilbert/internal/scope"
+	"github.com/go-gilbert/gilbert/internal/support/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/cover"
+)
+
+func TestAction_ExtraProfiles(t *testing.T) {
+	projectDir := t.TempDir()
+	for _, name := range []string{"e2e-1.out", "e2e-2.out"} {
+		require.NoError(t, os.WriteFile(filepath.Join(projectDir, name), []byte("mode: set\n"), 0644))
+	}
+
+	cases := map[string]struct {
+		params   params
+		expected []string
+		err      string
+	}{
+		"no extra profiles": {
+			expected: []string{},
+		},
+		"expand profile patterns": {
+			params: params{Profiles: []string{"e2e-*.out"}},
+			expected: []string{
+				filepath.Join(projectDir, "e2e-1.out"),
+				filepath.Join(projectDir, "e2e-2.out"),
+			},
+		},
+		"report missing profile": {
+			params: params{Profiles: []string{"unit.out"}},
+			err:    "cover profile 'unit.out' not found",
+		},
+		"report missing coverage directory": {
+			params: params{CoverDir: "coverdata"},
+			err:    "coverage data directory not found",
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			a := &Action{
+				scope:  scope.CreateScope(expr.SpecV2Parser{}, projectDir, nil),
+				params: c.params,
+			}
+
+			got, err := a.extraProfiles(job.NewRunContext(context.Background(), nil, &test.Log{T: t}))
+			if c.err != "" {
+				assert.ErrorContains(t, err, c.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, got)
+		})
+	}
+}
+
+func TestAction_ConvertCoverDir(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping binary coverage build in short mode")
+	}
+
+	projectDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "go.mod"), []byte("module example.com/app\n\ngo 1.22\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "main.go"), []byte("package main\n\nfunc main() {\n\tprintln(\"hello\")\n}\n"), 0644))
+
+	// build binary with coverage instrumentation and collect coverage data
+	binPath := filepath.Join(projectDir, "app")
+	build := exec.Command("go", "build", "-cover", "-o", binPath, ".")
+	build.Dir = projectDir
+	out, err := build.CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	coverDir := filepath.Join(projectDir, "coverdata")
+	require.NoError(t, os.Mkdir(coverDir, 0755))
+	run := exec.Command(binPath)
+	run.Env = append(os.Environ(), "GOCOVERDIR="+coverDir)
+	out, err = run.CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	a := &Action{
+		scope:  scope.CreateScope(expr.SpecV2Parser{}, projectDir, nil),
+		params: params{CoverDir: "coverdata"},
+	}
+
+	files, err := a.extraProfiles(job.NewRunContext(context.Background(), nil, &test.Log{T: t}))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, a.tempFiles, files)
+	defer os.Remove(files[0])
+
+	profiles, err := cover.ParseProfiles(files[0])
+	require.NoError(t, err)
+	require.Len(t, profiles, 1)
+	assert.Equal(t, "example.com/app/main.go", profiles[0].FileName)
+	assert.Equal(t, 1, profiles[0].Blocks[0].Count)
+}
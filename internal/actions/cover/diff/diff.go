@@ -0,0 +1,182 @@
+/*
+Package diff collects source lines changed since a git revision
+*/
+package diff
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-gilbert/gilbert/internal/actions/cover/profile"
+	"github.com/go-gilbert/gilbert/internal/support/git"
+)
+
+const (
+	newFilePrefix = "+++ "
+	hunkPrefix    = "@@ "
+	devNull       = "/dev/null"
+)
+
+// ChangedLines returns Go source lines added or modified since merge base of HEAD and base revision.
+//
+// Uncommitted changes of tracked files are also included, and all lines of untracked
+// (but not ignored) files are treated as added.
+func ChangedLines(ctx context.Context, dir, base string) (profile.ChangedLines, error) {
+	root, err := git.TopLevel(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	mergeBase, err := git.MergeBase(ctx, root, base)
+	if err != nil {
+		return nil, err
+	}
+
+	// prefixes are set explicitly since they can be changed by "diff.noprefix" or "diff.mnemonicPrefix" options
+	out, err := git.Output(ctx, root, "-c", "core.quotePath=false", "diff", "--unified=0", "--no-color", "--no-ext-diff",
+		"--src-prefix=a/", "--dst-prefix=b/", mergeBase, "--", "*.go")
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := Parse(bytes.NewReader(out), root)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := addUntracked(ctx, root, changes); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// addUntracked adds all lines of untracked Go files to changes
+func addUntracked(ctx context.Context, root string, changes profile.ChangedLines) error {
+	out, err := git.Output(ctx, root, "ls-files", "--others", "--exclude-standard", "-z", "--", "*.go")
+	if err != nil {
+		return err
+	}
+
+	for _, name := range strings.Split(string(out), "\x00") {
+		if name == "" {
+			continue
+		}
+
+		fileName := filepath.Join(root, filepath.FromSlash(name))
+		data, err := os.ReadFile(fileName)
+		if err != nil {
+			return fmt.Errorf("failed to read untracked file, %w", err)
+		}
+
+		count := bytes.Count(data, []byte("\n"))
+		if len(data) > 0 && data[len(data)-1] != '\n' {
+			count++
+		}
+
+		lines := make(map[int]struct{}, count)
+		for i := 1; i <= count; i++ {
+			lines[i] = struct{}{}
+		}
+
+		changes[fileName] = lines
+	}
+
+	return nil
+}
+
+// Parse parses unified diff and returns added lines.
+//
+// File paths are resolved relative to repository root.
+func Parse(r io.Reader, root string) (profile.ChangedLines, error) {
+	changes := make(profile.ChangedLines)
+	var lines map[int]struct{}
+
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), 1024*1024)
+	for s.Scan() {
+		l := s.Text()
+		switch {
+		case strings.HasPrefix(l, newFilePrefix):
+			name, err := parseFileName(strings.TrimPrefix(l, newFilePrefix))
+			if err != nil {
+				return nil, err
+			}
+
+			if name == devNull {
+				// file was removed
+				lines = nil
+				continue
+			}
+
+			name = strings.TrimPrefix(name, "b/")
+			lines = make(map[int]struct{})
+			changes[filepath.Join(root, filepath.FromSlash(name))] = lines
+		case strings.HasPrefix(l, hunkPrefix):
+			if lines == nil {
+				continue
+			}
+
+			start, count, err := parseHunkHeader(l)
+			if err != nil {
+				return nil, err
+			}
+
+			for i := start; i < start+count; i++ {
+				lines[i] = struct{}{}
+			}
+		}
+	}
+
+	return changes, s.Err()
+}
+
+// parseFileName returns file name of diff header.
+//
+// Names with special characters are quoted by git in C style,
+// names with spaces are followed by a tab.
+func parseFileName(name string) (string, error) {
+	name = strings.TrimSuffix(name, "\t")
+	if !strings.HasPrefix(name, `"`) {
+		return name, nil
+	}
+
+	out, err := strconv.Unquote(name)
+	if err != nil {
+		return "", fmt.Errorf("invalid diff file name %s", name)
+	}
+
+	return out, nil
+}
+
+// parseHunkHeader returns start line and lines count of new file hunk.
+//
+// Header format is "@@ -start[,count] +start[,count] @@".
+func parseHunkHeader(header string) (start, count int, err error) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+		return 0, 0, fmt.Errorf("invalid diff hunk header %q", header)
+	}
+
+	startStr, countStr, ok := strings.Cut(strings.TrimPrefix(fields[2], "+"), ",")
+	if start, err = strconv.Atoi(startStr); err != nil {
+		return 0, 0, fmt.Errorf("invalid diff hunk header %q", header)
+	}
+
+	if !ok {
+		return start, 1, nil
+	}
+
+	if count, err = strconv.Atoi(countStr); err != nil {
+		return 0, 0, fmt.Errorf("invalid diff hunk header %q", header)
+	}
+
+	return start, count, nil
+}
@@ -0,0 +1,153 @@
+package diff
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-gilbert/gilbert/internal/actions/cover/profile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleDiff = `diff --git a/foo/foo.go b/foo/foo.go
+index 3b18e51..a0b1c2d 100644
+--- a/foo/foo.go
++++ b/foo/foo.go
+@@ -3,0 +4,2 @@ package foo
++func Foo() {
++}
+@@ -10 +12 @@ func Bar() {
+-	return 1
++	return 2
+@@ -20,3 +21,0 @@ func Baz() {
+-	a := 1
+-	b := 2
+-	c := 3
+diff --git a/old.go b/old.go
+deleted file mode 100644
+--- a/old.go
++++ /dev/null
+@@ -1,2 +0,0 @@
+-package foo
+-
+`
+
+func TestParse(t *testing.T) {
+	changes, err := Parse(strings.NewReader(sampleDiff), "/repo")
+	require.NoError(t, err)
+	assert.Equal(t, profile.ChangedLines{
+		filepath.Join("/repo", "foo", "foo.go"): {4: {}, 5: {}, 12: {}},
+	}, changes)
+}
+
+func TestParse_QuotedNames(t *testing.T) {
+	src := "+++ b/foo bar.go\t\n@@ -1 +1 @@\n" +
+		"+++ \"b/tab\\there.go\"\n@@ -1 +2 @@\n" +
+		"+++ \"b/\\320\\244.go\"\n@@ -1 +3 @@\n"
+	changes, err := Parse(strings.NewReader(src), "/repo")
+	require.NoError(t, err)
+	assert.Equal(t, profile.ChangedLines{
+		filepath.Join("/repo", "foo bar.go"):   {1: {}},
+		filepath.Join("/repo", "tab\there.go"): {2: {}},
+		filepath.Join("/repo", "Ф.go"):         {3: {}},
+	}, changes)
+
+	_, err = Parse(strings.NewReader("+++ \"b/foo.go\n"), "/repo")
+	assert.Error(t, err)
+}
+
+func TestParseHunkHeader(t *testing.T) {
+	cases := map[string]struct {
+		header string
+		start  int
+		count  int
+		err    bool
+	}{
+		"range": {
+			header: "@@ -1,2 +3,4 @@ func Foo()",
+			start:  3,
+			count:  4,
+		},
+		"single line": {
+			header: "@@ -1 +3 @@",
+			start:  3,
+			count:  1,
+		},
+		"deletion": {
+			header: "@@ -1,2 +0,0 @@",
+			count:  0,
+		},
+		"invalid header": {
+			header: "@@ foo",
+			err:    true,
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			start, count, err := parseHunkHeader(c.header)
+			if c.err {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, c.start, start)
+			assert.Equal(t, c.count, count)
+		})
+	}
+}
+
+func TestChangedLines(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		args = append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	fileName := filepath.Join(dir, "foo.go")
+	git("init", "-q")
+	git("config", "diff.noprefix", "true")
+	require.NoError(t, os.WriteFile(fileName, []byte("package foo\n\nfunc Foo() {}\n"), 0644))
+	git("add", "-A")
+	git("commit", "-qm", "initial")
+	git("branch", "base")
+
+	require.NoError(t, os.WriteFile(fileName, []byte("package foo\n\nfunc Foo() {}\n\nfunc Bar() {}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Ф bar.go"), []byte("package foo\n"), 0644))
+	git("add", "-A")
+	git("commit", "-qm", "add bar")
+
+	// uncommitted changes and untracked files are included too
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("readme"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("ignored.go\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ignored.go"), []byte("package foo\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "new.go"), []byte("package foo\n\nfunc New() {}"), 0644))
+	require.NoError(t, os.WriteFile(fileName, []byte("package foo\n\nfunc Foo() {}\n\nfunc Bar() {}\n\nfunc Baz() {}\n"), 0644))
+
+	changes, err := ChangedLines(context.Background(), dir, "base")
+	require.NoError(t, err)
+
+	root, err := filepath.EvalSymlinks(dir)
+	require.NoError(t, err)
+	assert.Equal(t, profile.ChangedLines{
+		filepath.Join(root, "foo.go"):   {4: {}, 5: {}, 6: {}, 7: {}},
+		filepath.Join(root, "Ф bar.go"): {1: {}},
+		filepath.Join(root, "new.go"):   {1: {}, 2: {}, 3: {}},
+	}, changes)
+
+	_, err = ChangedLines(context.Background(), dir, "unknown")
+	assert.Error(t, err)
+}
@@ -0,0 +1,132 @@
+/*
+Package history contains code coverage history storage.
+
+Each cover run is recorded as a single JSON line with total and per package coverage
+keyed by git commit, so coverage trends can be compared between commits.
+*/
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-gilbert/gilbert/internal/actions/cover/profile"
+	"github.com/go-gilbert/gilbert/internal/storage"
+)
+
+// FileName is coverage history file name in project coverage storage
+const FileName = "history.jsonl"
+
+// Entry is coverage record of a single commit
+type Entry struct {
+	// Commit is git commit hash
+	Commit string `json:"commit"`
+
+	// Time is record time
+	Time time.Time `json:"time"`
+
+	// Total is total coverage percentage
+	Total float64 `json:"total"`
+
+	// Packages is coverage percentage of each package
+	Packages map[string]float64 `json:"packages"`
+}
+
+// ShortCommit returns abbreviated commit hash
+func (e Entry) ShortCommit() string {
+	if len(e.Commit) > 7 {
+		return e.Commit[:7]
+	}
+
+	return e.Commit
+}
+
+// NewEntry creates history entry from coverage report
+func NewEntry(commit string, r *profile.Report) Entry {
+	e := Entry{
+		Commit:   commit,
+		Time:     time.Now().UTC(),
+		Total:    r.Percentage(),
+		Packages: make(map[string]float64, len(r.Packages)),
+	}
+
+	for name, pkg := range r.Packages {
+		e.Packages[name] = pkg.Percentage()
+	}
+
+	return e
+}
+
+// Path returns coverage history file path of project
+func Path(projectDir string) (string, error) {
+	return storage.ProjectPath(projectDir, storage.Coverage, FileName)
+}
+
+// Load reads history entries from file in order of recording.
+//
+// Returns empty list if history file doesn't exist.
+func Load(fileName string) ([]Entry, error) {
+	data, err := os.ReadFile(fileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read coverage history, %w", err)
+	}
+
+	var entries []Entry
+	s := bufio.NewScanner(bytes.NewReader(data))
+	s.Buffer(nil, len(data)+1)
+	for line := 1; s.Scan(); line++ {
+		if len(bytes.TrimSpace(s.Bytes())) == 0 {
+			continue
+		}
+
+		var e Entry
+		if err := json.Unmarshal(s.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("malformed coverage history record at %s:%d, %w", fileName, line, err)
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, s.Err()
+}
+
+// Record adds entry to the history file.
+//
+// Previous record of the same commit is replaced by a new one.
+func Record(fileName string, e Entry) error {
+	entries, err := Load(fileName)
+	if err != nil {
+		return err
+	}
+
+	out := make([]Entry, 0, len(entries)+1)
+	for _, old := range entries {
+		if old.Commit != e.Commit {
+			out = append(out, old)
+		}
+	}
+
+	buff := &bytes.Buffer{}
+	enc := json.NewEncoder(buff)
+	for _, item := range append(out, e) {
+		if err := enc.Encode(item); err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+		return fmt.Errorf("failed to create coverage history directory, %w", err)
+	}
+
+	return os.WriteFile(fileName, buff.Bytes(), 0644)
+}
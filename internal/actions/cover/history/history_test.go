@@ -0,0 +1,44 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecord(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "coverage", FileName)
+	entries, err := Load(fileName)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	first := Entry{Commit: "aaa", Time: ts, Total: 50, Packages: map[string]float64{"foo": 50}}
+	second := Entry{Commit: "bbb", Time: ts, Total: 60, Packages: map[string]float64{"foo": 60}}
+	rerun := Entry{Commit: "aaa", Time: ts, Total: 55, Packages: map[string]float64{"foo": 55}}
+	for _, e := range []Entry{first, second, rerun} {
+		require.NoError(t, Record(fileName, e))
+	}
+
+	// record of the same commit replaces previous one
+	entries, err = Load(fileName)
+	require.NoError(t, err)
+	assert.Equal(t, []Entry{second, rerun}, entries)
+}
+
+func TestLoad_Malformed(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), FileName)
+	require.NoError(t, os.WriteFile(fileName, []byte("{\"commit\":\"aaa\"}\n\nfoo\n"), 0644))
+
+	_, err := Load(fileName)
+	assert.ErrorContains(t, err, "malformed coverage history record at "+fileName+":3")
+}
+
+func TestShortCommit(t *testing.T) {
+	assert.Equal(t, "0123456", Entry{Commit: "0123456789abcdef"}.ShortCommit())
+	assert.Equal(t, "abc", Entry{Commit: "abc"}.ShortCommit())
+}
@@ -0,0 +1,99 @@
+package history
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Change is coverage change of a package between two commits
+type Change struct {
+	Package string
+
+	// Previous is coverage at previous commit
+	Previous float64
+
+	// Current is coverage at current commit
+	Current float64
+
+	// Added is true if package is missing at previous commit
+	Added bool
+
+	// Removed is true if package is missing at current commit
+	Removed bool
+}
+
+// Delta returns coverage difference in percents
+func (c Change) Delta() float64 {
+	return c.Current - c.Previous
+}
+
+// Compare returns per package coverage changes between two entries sorted by package name
+func Compare(prev, cur Entry) []Change {
+	changes := make([]Change, 0, len(cur.Packages))
+	for name, val := range cur.Packages {
+		old, ok := prev.Packages[name]
+		changes = append(changes, Change{Package: name, Previous: old, Current: val, Added: !ok})
+	}
+
+	for name, old := range prev.Packages {
+		if _, ok := cur.Packages[name]; !ok {
+			changes = append(changes, Change{Package: name, Previous: old, Removed: true})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Package < changes[j].Package
+	})
+
+	return changes
+}
+
+// Previous returns the latest entry recorded for a commit other than the specified one.
+//
+// Returns false if there is no such entry.
+func Previous(entries []Entry, commit string) (Entry, bool) {
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Commit != commit {
+			return entries[i], true
+		}
+	}
+
+	return Entry{}, false
+}
+
+// Regressions returns packages which coverage dropped by more than max drop
+func Regressions(changes []Change, maxDrop float64) []Change {
+	var out []Change
+	for _, c := range changes {
+		if c.Added || c.Removed {
+			continue
+		}
+
+		if -c.Delta() > maxDrop {
+			out = append(out, c)
+		}
+	}
+
+	return out
+}
+
+// CheckDrop checks that total and per package coverage didn't drop by more than max drop
+// since previous entry.
+func CheckDrop(prev, cur Entry, maxDrop float64) error {
+	var msgs []string
+	if drop := prev.Total - cur.Total; drop > maxDrop {
+		msgs = append(msgs, fmt.Sprintf("  - total: %.2f%% -> %.2f%% (-%.2f%%)", prev.Total, cur.Total, drop))
+	}
+
+	for _, c := range Regressions(Compare(prev, cur), maxDrop) {
+		msgs = append(msgs, fmt.Sprintf("  - %s: %.2f%% -> %.2f%% (-%.2f%%)", c.Package, c.Previous, c.Current, -c.Delta()))
+	}
+
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("code coverage dropped by more than %.2f%% since commit %s:\n%s",
+		maxDrop, prev.ShortCommit(), strings.Join(msgs, "\n"))
+}
@@ -0,0 +1,95 @@
+package history
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompare(t *testing.T) {
+	prev := Entry{Packages: map[string]float64{"bar": 80, "baz": 40, "foo": 50}}
+	cur := Entry{Packages: map[string]float64{"bar": 70, "foo": 60, "qux": 10}}
+	assert.Equal(t, []Change{
+		{Package: "bar", Previous: 80, Current: 70},
+		{Package: "baz", Previous: 40, Removed: true},
+		{Package: "foo", Previous: 50, Current: 60},
+		{Package: "qux", Current: 10, Added: true},
+	}, Compare(prev, cur))
+}
+
+func TestCheckDrop(t *testing.T) {
+	prev := Entry{Commit: "0123456789", Total: 70, Packages: map[string]float64{"bar": 80, "foo": 50}}
+	cases := map[string]struct {
+		cur     Entry
+		maxDrop float64
+		err     string
+	}{
+		"coverage increased": {
+			cur: Entry{Total: 75, Packages: map[string]float64{"bar": 80, "foo": 60}},
+		},
+		"drop within limit": {
+			cur:     Entry{Total: 69, Packages: map[string]float64{"bar": 79, "foo": 50}},
+			maxDrop: 1,
+		},
+		"new and removed packages are ignored": {
+			cur: Entry{Total: 70, Packages: map[string]float64{"bar": 80, "qux": 0}},
+		},
+		"total and package drop": {
+			cur:     Entry{Total: 65, Packages: map[string]float64{"bar": 70, "foo": 49.5}},
+			maxDrop: 1,
+			err: "code coverage dropped by more than 1.00% since commit 0123456:\n" +
+				"  - total: 70.00% -> 65.00% (-5.00%)\n" +
+				"  - bar: 80.00% -> 70.00% (-10.00%)",
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			err := CheckDrop(prev, c.cur, c.maxDrop)
+			if c.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.EqualError(t, err, c.err)
+		})
+	}
+}
+
+func TestPrevious(t *testing.T) {
+	entries := []Entry{{Commit: "a"}, {Commit: "b", Total: 1}, {Commit: "b", Total: 2}, {Commit: "c"}}
+	cases := map[string]struct {
+		entries []Entry
+		commit  string
+		expect  Entry
+		ok      bool
+	}{
+		"empty history": {
+			commit: "a",
+		},
+		"only current commit": {
+			entries: []Entry{{Commit: "a"}},
+			commit:  "a",
+		},
+		"skip entries of current commit": {
+			entries: entries[:3],
+			commit:  "b",
+			expect:  Entry{Commit: "a"},
+			ok:      true,
+		},
+		"latest entry of another commit": {
+			entries: entries,
+			commit:  "c",
+			expect:  Entry{Commit: "b", Total: 2},
+			ok:      true,
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			got, ok := Previous(c.entries, c.commit)
+			assert.Equal(t, c.ok, ok)
+			assert.Equal(t, c.expect, got)
+		})
+	}
+}
@@ -1,28 +1,37 @@
 package html
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/go-gilbert/gilbert/internal/actions/cover/profile"
 	"github.com/go-gilbert/gilbert/internal/manifest"
 	"github.com/go-gilbert/gilbert/internal/runner"
 	"github.com/go-gilbert/gilbert/internal/runner/job"
 	"github.com/go-gilbert/gilbert/internal/scope"
+	"github.com/go-gilbert/gilbert/internal/storage"
 	"github.com/go-gilbert/gilbert/internal/support/shell"
 )
 
 const (
 	coverFilePattern   = "gbcover*.out"
+	defaultReportDir   = "html"
 	defaultCoverTarget = "./..."
-	defaultTimeout     = manifest.Period(300)
+	defaultAddress     = "localhost:0"
+	shutdownTimeout    = 5 * time.Second
 )
 
 // NewAction creates a new html coverage report action handler
 func NewAction(scope *scope.Scope, params manifest.ActionParams) (h runner.ActionHandler, err error) {
-	handler := &reportAction{alive: true, scope: scope, Timeout: defaultTimeout}
+	handler := &reportAction{alive: true, scope: scope, Address: defaultAddress}
 	if err := params.Unmarshal(&handler); err != nil {
 		return nil, err
 	}
@@ -31,6 +40,21 @@ func NewAction(scope *scope.Scope, params manifest.ActionParams) (h runner.Actio
 		handler.Packages = []string{defaultCoverTarget}
 	}
 
+	if err := scope.Scan(&handler.OutputDir, &handler.Address); err != nil {
+		return nil, err
+	}
+
+	// Report is written to project coverage storage if output directory is not set
+	projectDir := scope.Environment().ProjectDirectory
+	if handler.OutputDir == "" {
+		handler.OutputDir, err = storage.ProjectPath(projectDir, storage.Coverage, defaultReportDir)
+		if err != nil {
+			return nil, err
+		}
+	} else if !filepath.IsAbs(handler.OutputDir) {
+		handler.OutputDir = filepath.Join(projectDir, handler.OutputDir)
+	}
+
 	handler.coverFile, err = os.CreateTemp(os.TempDir(), coverFilePattern)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create coverage temporary file: %w", err)
@@ -40,8 +64,19 @@ func NewAction(scope *scope.Scope, params manifest.ActionParams) (h runner.Actio
 }
 
 type reportAction struct {
-	Packages  []string        `mapstructure:"packages"`
-	Timeout   manifest.Period `mapstructure:"timeout"`
+	Packages []string `mapstructure:"packages"`
+
+	// OutputDir is directory to write HTML report.
+	//
+	// Defaults to "coverage/html" directory in project storage.
+	OutputDir string `mapstructure:"outputDir"`
+
+	// Serve enables report HTTP server until job is cancelled
+	Serve bool `mapstructure:"serve"`
+
+	// Address is report HTTP server listen address
+	Address string `mapstructure:"address"`
+
 	scope     *scope.Scope
 	coverFile *os.File
 	alive     bool
@@ -50,7 +85,7 @@ type reportAction struct {
 func (a *reportAction) Call(ctx *job.RunContext, r *runner.TaskRunner) (err error) {
 	defer a.clean(ctx)
 	ctx.Log().Info("Generating coverage profile...")
-	if err := a.createReport(ctx); err != nil {
+	if err := a.createProfile(ctx); err != nil {
 		return err
 	}
 
@@ -58,26 +93,59 @@ func (a *reportAction) Call(ctx *job.RunContext, r *runner.TaskRunner) (err erro
 		return nil
 	}
 
-	ctx.Log().Info("Opening report...")
-	if err := a.openReport(ctx); err != nil {
-		return fmt.Errorf("failed to open report in browser, %s", err)
+	pkgs, err := profile.ConvertProfiles(a.coverFile.Name())
+	if err != nil {
+		return fmt.Errorf("failed to parse cover profile file, %s", err)
 	}
 
-	time.Sleep(a.Timeout.ToDuration())
-	return nil
+	report := profile.Create(*pkgs)
+	fileName, err := writeReport(a.OutputDir, a.scope.Environment().ProjectDirectory, &report)
+	if err != nil {
+		return err
+	}
+
+	ctx.Log().Infof("Coverage report saved to %q", fileName)
+	if !a.Serve {
+		return nil
+	}
+
+	return a.serveReport(ctx, a.OutputDir)
 }
 
-func (a *reportAction) openReport(ctx *job.RunContext) error {
-	// go tool cover -html=/tmp/cover.out
-	cmd := exec.CommandContext(ctx.Context(), "go", "tool", "cover", "-html="+a.coverFile.Name())
-	cmd.Dir = a.scope.Environment().ProjectDirectory
-	cmd.Stdout = ctx.Log()
-	cmd.Stderr = ctx.Log().ErrorWriter()
-	ctx.Log().Debugf("cover:html: exec '%s'", strings.Join(cmd.Args, " "))
-	return cmd.Run()
+// serveReport serves report directory until job is cancelled
+func (a *reportAction) serveReport(ctx *job.RunContext, dir string) error {
+	l, err := net.Listen("tcp", a.Address)
+	if err != nil {
+		return fmt.Errorf("failed to start report server, %w", err)
+	}
+
+	srv := &http.Server{
+		Handler:           http.FileServer(http.Dir(dir)),
+		ReadHeaderTimeout: shutdownTimeout,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Serve(l)
+	}()
+
+	ctx.Log().Infof("Serving coverage report at http://%s/ (press Ctrl+C to stop)", l.Addr())
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("report server stopped, %w", err)
+	case <-ctx.Context().Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		ctx.Log().Debugf("cover:html: failed to stop report server: %s", err)
+	}
+
+	return nil
 }
 
-func (a *reportAction) createReport(ctx *job.RunContext) error {
+func (a *reportAction) createProfile(ctx *job.RunContext) error {
 	// pass package names as is, since '-coverpkg' doesn't recognise them in CSV format (go 1.11+)
 	args := []string{"test", "-coverprofile=" + a.coverFile.Name()}
 	for _, pkg := range a.Packages {
@@ -89,7 +157,6 @@ func (a *reportAction) createReport(ctx *job.RunContext) error {
 		args = append(args, val)
 	}
 
-	//cmd := exec.CommandContext(ctx.Context(), "go", args...)
 	cmd := exec.CommandContext(ctx.Context(), "go", args...)
 	cmd.Dir = a.scope.Environment().ProjectDirectory
 	cmd.Stdout = ctx.Log()
@@ -119,6 +186,10 @@ func (a *reportAction) clean(ctx *job.RunContext) {
 }
 
 func (a *reportAction) Cancel(ctx *job.RunContext) error {
-	a.clean(ctx)
+	// report server is stopped by job context
+	if !a.Serve {
+		a.clean(ctx)
+	}
+
 	return nil
 }
@@ -0,0 +1,162 @@
+package html
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-gilbert/gilbert/internal/actions/cover/profile"
+)
+
+// reportFileName is HTML report file name in output directory
+const reportFileName = "index.html"
+
+const (
+	lineCovered   = "covered"
+	lineUncovered = "uncovered"
+)
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"percent": func(c profile.Coverage) string {
+		return fmt.Sprintf("%.2f%%", c.Percentage())
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Coverage report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { text-align: left; padding: .25em 1em; border-bottom: 1px solid #ddd; }
+td.num { text-align: right; }
+h2 { font-size: 1.1em; margin-top: 2em; }
+pre { font-family: monospace; background: #fafafa; border: 1px solid #ddd; padding: .5em 0; overflow-x: auto; }
+pre span.line { display: block; padding: 0 1em; }
+pre span.ln { display: inline-block; width: 4em; color: #999; user-select: none; }
+.covered { background: #d4f8d4; }
+.uncovered { background: #fcd9d9; }
+</style>
+</head>
+<body>
+<h1>Coverage report</h1>
+<p>Total coverage: <b>{{percent .Coverage}}</b> ({{.Reached}} of {{.Total}} statements)</p>
+<table>
+<tr><th>Package</th><th>File</th><th>Statements</th><th>Coverage</th></tr>
+{{- range .Files}}
+<tr><td>{{.Package}}</td><td><a href="#{{.ID}}">{{.Name}}</a></td><td class="num">{{.Total}}</td><td class="num">{{percent .Coverage}}</td></tr>
+{{- end}}
+</table>
+{{- range .Files}}
+<h2 id="{{.ID}}">{{.Name}} &mdash; {{percent .Coverage}}</h2>
+<pre>
+{{- range .Lines}}<span class="line {{.Class}}"><span class="ln">{{.Number}}</span>{{.Text}}</span>{{end -}}
+</pre>
+{{- end}}
+</body>
+</html>
+`))
+
+type reportData struct {
+	profile.Coverage
+	Files []fileData
+}
+
+type fileData struct {
+	profile.Coverage
+	ID      string
+	Package string
+	Name    string
+	Lines   []lineData
+}
+
+type lineData struct {
+	Number int
+	Text   string
+	Class  string
+}
+
+// writeReport renders HTML coverage report into output directory.
+//
+// Returns report file path.
+func writeReport(outputDir, sourceDir string, r *profile.Report) (string, error) {
+	data, err := newReportData(sourceDir, r)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create report directory, %w", err)
+	}
+
+	fileName := filepath.Join(outputDir, reportFileName)
+	f, err := os.Create(fileName)
+	if err != nil {
+		return "", fmt.Errorf("failed to create report file, %w", err)
+	}
+
+	if err := reportTemplate.Execute(f, data); err != nil {
+		_ = f.Close()
+		return "", fmt.Errorf("failed to render report, %w", err)
+	}
+
+	return fileName, f.Close()
+}
+
+func newReportData(sourceDir string, r *profile.Report) (*reportData, error) {
+	data := &reportData{Coverage: r.Coverage}
+	for _, pkgName := range r.Packages.Sort(profile.ByName, false) {
+		pkg := r.Packages[pkgName]
+		for _, fileName := range pkg.FileNames() {
+			src, err := os.ReadFile(fileName)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read source file, %w", err)
+			}
+
+			f := pkg.Files[fileName]
+			data.Files = append(data.Files, fileData{
+				Coverage: f.Coverage,
+				ID:       fmt.Sprintf("file%d", len(data.Files)),
+				Package:  pkgName,
+				Name:     relPath(sourceDir, fileName),
+				Lines:    highlightLines(string(src), f.Lines()),
+			})
+		}
+	}
+
+	return data, nil
+}
+
+// highlightLines splits source into lines marked by coverage status
+func highlightLines(src string, hits []profile.LineHits) []lineData {
+	classes := make(map[int]string, len(hits))
+	for _, h := range hits {
+		if h.Hits > 0 {
+			classes[h.Line] = lineCovered
+		} else {
+			classes[h.Line] = lineUncovered
+		}
+	}
+
+	text := strings.Split(strings.TrimSuffix(src, "\n"), "\n")
+	lines := make([]lineData, 0, len(text))
+	for i, t := range text {
+		lines = append(lines, lineData{
+			Number: i + 1,
+			Text:   strings.ReplaceAll(t, "\t", "    "),
+			Class:  classes[i+1],
+		})
+	}
+
+	return lines
+}
+
+func relPath(sourceDir, fileName string) string {
+	if rel, err := filepath.Rel(sourceDir, fileName); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+
+	return filepath.ToSlash(fileName)
+}
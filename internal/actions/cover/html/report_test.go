@@ -0,0 +1,117 @@
+package html
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axw/gocov"
+	"github.com/axw/gocov/gocovutil"
+	"github.com/go-gilbert/gilbert/internal/actions/cover/profile"
+	"github.com/go-gilbert/gilbert/internal/runner/job"
+	"github.com/go-gilbert/gilbert/internal/support/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const source = `package foo
+
+func Foo() bool {
+	return 1 < 2
+}
+
+func Bar() {
+	println("<bar>")
+}
+`
+
+func TestWriteReport(t *testing.T) {
+	srcDir, outDir := t.TempDir(), filepath.Join(t.TempDir(), "report")
+	fileName := filepath.Join(srcDir, "foo.go")
+	require.NoError(t, os.WriteFile(fileName, []byte(source), 0644))
+
+	stmt := func(s string, reached int64) *gocov.Statement {
+		start := strings.Index(source, s)
+		return &gocov.Statement{Start: start, End: start + len(s), Reached: reached}
+	}
+
+	r := profile.Create(gocovutil.Packages{
+		{
+			Name: "example.com/foo",
+			Functions: []*gocov.Function{
+				{Name: "Foo", File: fileName, Start: strings.Index(source, "func Foo"), Statements: []*gocov.Statement{stmt("return 1 < 2", 1)}},
+				{Name: "Bar", File: fileName, Start: strings.Index(source, "func Bar"), Statements: []*gocov.Statement{stmt(`println("<bar>")`, 0)}},
+			},
+		},
+	})
+
+	reportFile, err := writeReport(outDir, srcDir, &r)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(outDir, reportFileName), reportFile)
+
+	data, err := os.ReadFile(reportFile)
+	require.NoError(t, err)
+	out := string(data)
+	assert.Contains(t, out, "Total coverage: <b>50.00%</b> (1 of 2 statements)")
+	assert.Contains(t, out, `<a href="#file0">foo.go</a>`)
+	assert.Contains(t, out, `<span class="line covered"><span class="ln">4</span>    return 1 &lt; 2</span>`)
+	assert.Contains(t, out, `<span class="line uncovered"><span class="ln">8</span>    println(&#34;&lt;bar&gt;&#34;)</span>`)
+	assert.Contains(t, out, `<span class="line "><span class="ln">1</span>package foo</span>`)
+}
+
+func TestWriteReport_MissingSource(t *testing.T) {
+	r := profile.Create(gocovutil.Packages{
+		{
+			Name:      "example.com/foo",
+			Functions: []*gocov.Function{{Name: "Foo", File: filepath.Join(t.TempDir(), "foo.go")}},
+		},
+	})
+
+	_, err := writeReport(t.TempDir(), "", &r)
+	assert.ErrorContains(t, err, "failed to read source file")
+}
+
+func TestReportAction_ServeReport(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, reportFileName), []byte("report"), 0644))
+
+	// reserve free port for server
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := &reportAction{Address: addr}
+	done := make(chan error, 1)
+	go func() {
+		done <- a.serveReport(job.NewRunContext(ctx, nil, &test.Log{T: t}), dir)
+	}()
+
+	var rsp *http.Response
+	require.Eventually(t, func() bool {
+		rsp, err = http.Get("http://" + addr + "/")
+		return err == nil
+	}, 5*time.Second, 50*time.Millisecond)
+
+	body, err := io.ReadAll(rsp.Body)
+	_ = rsp.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "report", string(body))
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server wasn't stopped after job cancel")
+	}
+}
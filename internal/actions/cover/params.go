@@ -2,7 +2,10 @@ package cover
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
+	"github.com/go-gilbert/gilbert/internal/actions/cover/badge"
 	"github.com/go-gilbert/gilbert/internal/actions/cover/profile"
 )
 
@@ -11,6 +14,9 @@ import (
 // go test -coverprofile=/tmp/cover -json ./services/foo ./services/bar./services/baz
 const toolArgsPrefixSize = 3
 
+// showFunctionsPrefix is prefix of functions coverage filter
+const showFunctionsPrefix = "below:"
+
 type params struct {
 	Threshold     float64   `mapstructure:"threshold"`
 	Report        bool      `mapstructure:"reportCoverage"`
@@ -18,6 +24,61 @@ type params struct {
 	FullReport    bool      `mapstructure:"fullReport"`
 	Packages      []string  `mapstructure:"packages"`
 	Sort          sortParam `mapstructure:"sort"`
+
+	// Output is list of files to export coverage report
+	Output []outputParam `mapstructure:"output"`
+
+	// Thresholds is minimal coverage by package pattern
+	Thresholds profile.Thresholds `mapstructure:"thresholds"`
+
+	// Exclude is list of file and package patterns excluded from report
+	Exclude []string `mapstructure:"exclude"`
+
+	// DiffAgainst is git revision to compare with.
+	//
+	// If set, threshold is checked only for statements changed since that revision.
+	DiffAgainst string `mapstructure:"diffAgainst"`
+
+	// Profiles is list of additional cover profiles (or glob patterns) to merge into report
+	Profiles []string `mapstructure:"profiles"`
+
+	// CoverDir is directory with binary coverage data written by "go build -cover" binaries (GOCOVERDIR)
+	CoverDir string `mapstructure:"coverDir"`
+
+	// RecordHistory enables recording of coverage history in project storage
+	RecordHistory bool `mapstructure:"recordHistory"`
+
+	// MaxDrop is max allowed coverage drop in percents since previous recorded commit.
+	//
+	// Negative value disables the check. Requires history recording.
+	MaxDrop float64 `mapstructure:"maxDrop"`
+
+	// Badge is SVG coverage badge options
+	Badge badgeParam `mapstructure:"badge"`
+
+	// ShowFunctions is filter of functions to list with uncovered lines in "below:<percent>" format
+	ShowFunctions string `mapstructure:"showFunctions"`
+}
+
+// functionsThreshold returns coverage threshold of functions to show.
+//
+// Returns false if functions list is disabled.
+func (p *params) functionsThreshold() (float64, bool, error) {
+	if p.ShowFunctions == "" {
+		return 0, false, nil
+	}
+
+	val, ok := strings.CutPrefix(p.ShowFunctions, showFunctionsPrefix)
+	if !ok {
+		return 0, false, fmt.Errorf("invalid showFunctions value '%s' (expected %s<percent>)", p.ShowFunctions, showFunctionsPrefix)
+	}
+
+	threshold, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(val), "%"), 64)
+	if err != nil || threshold < 0 || threshold > 100 {
+		return 0, false, fmt.Errorf("showFunctions coverage should be a number between 0 and 100 (got '%s')", val)
+	}
+
+	return threshold, true, nil
 }
 
 func (p *params) validate() error {
@@ -29,7 +90,33 @@ func (p *params) validate() error {
 		return fmt.Errorf("unsupported sort key '%s' (expected %s or %s)", p.Sort.By, profile.ByCoverage, profile.ByName)
 	}
 
-	return nil
+	for _, o := range p.Output {
+		if err := o.validate(); err != nil {
+			return err
+		}
+	}
+
+	for pattern, threshold := range p.Thresholds {
+		if err := profile.ValidatePattern(pattern); err != nil {
+			return err
+		}
+
+		if threshold > 100 || threshold < 0 {
+			return fmt.Errorf("coverage threshold of '%s' should be between 0 and 100 (got %f)", pattern, threshold)
+		}
+	}
+
+	for _, pattern := range p.Exclude {
+		if err := profile.ValidatePattern(pattern); err != nil {
+			return err
+		}
+	}
+
+	if _, _, err := p.functionsThreshold(); err != nil {
+		return err
+	}
+
+	return p.Badge.Bands.Validate()
 }
 
 type sortParam struct {
@@ -37,11 +124,44 @@ type sortParam struct {
 	Desc bool   `mapstructure:"desc"`
 }
 
+// outputParam is coverage report export destination
+type outputParam struct {
+	Format string `mapstructure:"format"`
+	Path   string `mapstructure:"path"`
+}
+
+func (o outputParam) validate() error {
+	if o.Path == "" {
+		return fmt.Errorf("missing output path for '%s' report", o.Format)
+	}
+
+	for _, f := range profile.ExportFormats {
+		if f == o.Format {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unsupported output format '%s' (expected %s)", o.Format, strings.Join(profile.ExportFormats, ", "))
+}
+
+// badgeParam is coverage badge destination and appearance
+type badgeParam struct {
+	// Path is badge file path. Badge is not generated if path is empty.
+	Path string `mapstructure:"path"`
+
+	// Label is badge label text
+	Label string `mapstructure:"label"`
+
+	// Bands is list of badge colors by minimal coverage
+	Bands badge.Bands `mapstructure:"bands"`
+}
+
 func newParams() params {
 	return params{
 		Threshold:     0.0,
 		Report:        true,
 		ShowUncovered: false,
+		MaxDrop:       -1,
 		Sort: sortParam{
 			By:   profile.ByCoverage,
 			Desc: true,
@@ -4,6 +4,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/go-gilbert/gilbert/internal/actions/cover/badge"
+	"github.com/go-gilbert/gilbert/internal/actions/cover/profile"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -31,6 +33,70 @@ func TestParamsValidate(t *testing.T) {
 				Sort:      sortParam{},
 			},
 		},
+		"validate output format": {
+			err: "unsupported output format 'html'",
+			p: params{
+				Sort:   sortParam{By: profile.ByName},
+				Output: []outputParam{{Format: "html", Path: "cover.html"}},
+			},
+		},
+		"validate output path": {
+			err: "missing output path for 'lcov' report",
+			p: params{
+				Sort:   sortParam{By: profile.ByName},
+				Output: []outputParam{{Format: profile.FormatLCOV}},
+			},
+		},
+		"validate package thresholds": {
+			err: "coverage threshold of 'example.com/foo' should be between 0 and 100",
+			p: params{
+				Sort:       sortParam{By: profile.ByName},
+				Thresholds: profile.Thresholds{"example.com/foo": 120},
+			},
+		},
+		"validate exclude patterns": {
+			err: "invalid pattern '[mock'",
+			p: params{
+				Sort:    sortParam{By: profile.ByName},
+				Exclude: []string{"[mock"},
+			},
+		},
+		"validate badge bands": {
+			err: "missing color of badge band with min coverage 50.00",
+			p: params{
+				Sort:  sortParam{By: profile.ByName},
+				Badge: badgeParam{Path: "coverage.svg", Bands: badge.Bands{{Min: 50}}},
+			},
+		},
+		"validate showFunctions format": {
+			err: "invalid showFunctions value 'above:50' (expected below:<percent>)",
+			p: params{
+				Sort:          sortParam{By: profile.ByName},
+				ShowFunctions: "above:50",
+			},
+		},
+		"validate showFunctions percent": {
+			err: "showFunctions coverage should be a number between 0 and 100 (got '120')",
+			p: params{
+				Sort:          sortParam{By: profile.ByName},
+				ShowFunctions: "below:120",
+			},
+		},
+		"accept showFunctions": {
+			p: params{
+				Sort:          sortParam{By: profile.ByName},
+				ShowFunctions: "below: 75.5%",
+			},
+		},
+		"accept output params": {
+			p: params{
+				Sort: sortParam{By: profile.ByName},
+				Output: []outputParam{
+					{Format: profile.FormatCobertura, Path: "coverage.xml"},
+					{Format: profile.FormatJSON, Path: "coverage.json"},
+				},
+			},
+		},
 	}
 
 	for n, c := range cases {
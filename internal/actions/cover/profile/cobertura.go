@@ -0,0 +1,137 @@
+package profile
+
+import (
+	"encoding/xml"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+const coberturaDocType = `<!DOCTYPE coverage SYSTEM "http://cobertura.sourceforge.net/xml/coverage-04.dtd">` + "\n"
+
+type coberturaCoverage struct {
+	XMLName         xml.Name           `xml:"coverage"`
+	LineRate        float64            `xml:"line-rate,attr"`
+	BranchRate      float64            `xml:"branch-rate,attr"`
+	LinesCovered    int                `xml:"lines-covered,attr"`
+	LinesValid      int                `xml:"lines-valid,attr"`
+	BranchesCovered int                `xml:"branches-covered,attr"`
+	BranchesValid   int                `xml:"branches-valid,attr"`
+	Complexity      float64            `xml:"complexity,attr"`
+	Timestamp       int64              `xml:"timestamp,attr"`
+	Sources         []string           `xml:"sources>source"`
+	Packages        []coberturaPackage `xml:"packages>package"`
+}
+
+type coberturaPackage struct {
+	Name       string           `xml:"name,attr"`
+	LineRate   float64          `xml:"line-rate,attr"`
+	BranchRate float64          `xml:"branch-rate,attr"`
+	Complexity float64          `xml:"complexity,attr"`
+	Classes    []coberturaClass `xml:"classes>class"`
+}
+
+type coberturaClass struct {
+	Name       string            `xml:"name,attr"`
+	FileName   string            `xml:"filename,attr"`
+	LineRate   float64           `xml:"line-rate,attr"`
+	BranchRate float64           `xml:"branch-rate,attr"`
+	Complexity float64           `xml:"complexity,attr"`
+	Methods    []coberturaMethod `xml:"methods>method"`
+	Lines      []coberturaLine   `xml:"lines>line"`
+}
+
+type coberturaMethod struct {
+	Name       string          `xml:"name,attr"`
+	Signature  string          `xml:"signature,attr"`
+	LineRate   float64         `xml:"line-rate,attr"`
+	BranchRate float64         `xml:"branch-rate,attr"`
+	Complexity float64         `xml:"complexity,attr"`
+	Lines      []coberturaLine `xml:"lines>line"`
+}
+
+type coberturaLine struct {
+	Number int   `xml:"number,attr"`
+	Hits   int64 `xml:"hits,attr"`
+}
+
+// writeCobertura writes report in Cobertura XML format
+func (r *Report) writeCobertura(w io.Writer, sourceDir string) error {
+	out := coberturaCoverage{
+		Timestamp: time.Now().UnixMilli(),
+		Packages:  make([]coberturaPackage, 0, len(r.Packages)),
+	}
+
+	if sourceDir != "" {
+		out.Sources = []string{sourceDir}
+	}
+
+	for _, pkgName := range r.Packages.Sort(ByName, false) {
+		pkg := r.Packages[pkgName]
+		p := coberturaPackage{
+			Name:    pkgName,
+			Classes: make([]coberturaClass, 0, len(pkg.Files)),
+		}
+
+		pkgTotal, pkgCovered := 0, 0
+		for _, fileName := range pkg.FileNames() {
+			c := newCoberturaClass(relPath(sourceDir, fileName), pkg.Files[fileName])
+			total, covered := lineCoverage(pkg.Files[fileName].Lines())
+			pkgTotal += total
+			pkgCovered += covered
+			p.Classes = append(p.Classes, c)
+		}
+
+		p.LineRate = rate(pkgCovered, pkgTotal)
+		out.LinesValid += pkgTotal
+		out.LinesCovered += pkgCovered
+		out.Packages = append(out.Packages, p)
+	}
+
+	out.LineRate = rate(out.LinesCovered, out.LinesValid)
+	if _, err := io.WriteString(w, xml.Header+coberturaDocType); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func newCoberturaClass(fileName string, f *FileReport) coberturaClass {
+	lines := f.Lines()
+	total, covered := lineCoverage(lines)
+	c := coberturaClass{
+		Name:     strings.TrimSuffix(path.Base(fileName), ".go"),
+		FileName: fileName,
+		LineRate: rate(covered, total),
+		Methods:  make([]coberturaMethod, 0, len(f.Functions)),
+		Lines:    newCoberturaLines(lines),
+	}
+
+	for _, fn := range f.Functions {
+		total, covered := lineCoverage(fn.Lines)
+		c.Methods = append(c.Methods, coberturaMethod{
+			Name:     fn.Name,
+			LineRate: rate(covered, total),
+			Lines:    newCoberturaLines(fn.Lines),
+		})
+	}
+
+	return c
+}
+
+func newCoberturaLines(lines []LineHits) []coberturaLine {
+	out := make([]coberturaLine, 0, len(lines))
+	for _, l := range lines {
+		out = append(out, coberturaLine{Number: l.Line, Hits: l.Hits})
+	}
+
+	return out
+}
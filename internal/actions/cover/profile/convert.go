@@ -34,26 +34,27 @@ import (
 	"github.com/axw/gocov"
 )
 
-// ConvertProfiles converts "go test" profiles to package coverage report
+// ConvertProfiles converts "go test" profiles to package coverage report.
+//
+// Profiles are merged before conversion, so the same code covered by several profiles is counted once.
 func ConvertProfiles(filenames ...string) (*gocovutil.Packages, error) {
-	ps := &gocovutil.Packages{}
-	for i := range filenames {
-		converter := Converter{
-			packages: make(map[string]*gocov.Package),
-		}
-		profiles, err := cover.ParseProfiles(filenames[i])
-		if err != nil {
+	profiles, err := MergeProfiles(filenames...)
+	if err != nil {
+		return nil, err
+	}
+
+	converter := Converter{
+		packages: make(map[string]*gocov.Package),
+	}
+	for _, p := range profiles {
+		if err := converter.convertProfile(p); err != nil {
 			return nil, err
 		}
-		for _, p := range profiles {
-			if err := converter.convertProfile(p); err != nil {
-				return nil, err
-			}
-		}
+	}
 
-		for _, pkg := range converter.packages {
-			ps.AddPackage(pkg)
-		}
+	ps := &gocovutil.Packages{}
+	for _, pkg := range converter.packages {
+		ps.AddPackage(pkg)
 	}
 
 	return ps, nil
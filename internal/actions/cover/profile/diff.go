@@ -0,0 +1,149 @@
+package profile
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/axw/gocov/gocovutil"
+)
+
+// ChangedLines is a set of changed line numbers by source file path
+type ChangedLines map[string]map[int]struct{}
+
+// LineRange is a range of source lines
+type LineRange struct {
+	Start int
+	End   int
+}
+
+// String implements fmt.Stringer
+func (r LineRange) String() string {
+	if r.Start == r.End {
+		return fmt.Sprint(r.Start)
+	}
+
+	return fmt.Sprintf("%d-%d", r.Start, r.End)
+}
+
+// DiffReport is coverage report of changed statements
+type DiffReport struct {
+	Coverage
+
+	// Uncovered contains ranges of uncovered changed lines by file name
+	Uncovered map[string][]LineRange
+}
+
+// CreateDiff creates coverage report only for statements located on changed lines
+func CreateDiff(pkgs gocovutil.Packages, changes ChangedLines) DiffReport {
+	r := DiffReport{Uncovered: make(map[string][]LineRange)}
+	idx := make(lineIndex)
+	uncovered := make(map[string]map[int]struct{})
+	for _, pkg := range pkgs {
+		for _, fn := range pkg.Functions {
+			changed, ok := changes[fn.File]
+			if !ok {
+				continue
+			}
+
+			for _, s := range fn.Statements {
+				start, end := idx.line(fn.File, s.Start), idx.line(fn.File, s.End)
+				if !linesChanged(changed, start, end) {
+					continue
+				}
+
+				r.Total++
+				if s.Reached > 0 {
+					r.Reached++
+					continue
+				}
+
+				if uncovered[fn.File] == nil {
+					uncovered[fn.File] = make(map[int]struct{})
+				}
+
+				for l := start; l <= end; l++ {
+					if _, ok := changed[l]; ok {
+						uncovered[fn.File][l] = struct{}{}
+					}
+				}
+			}
+		}
+	}
+
+	for fileName, lines := range uncovered {
+		r.Uncovered[fileName] = lineRanges(lines)
+	}
+
+	return r
+}
+
+// FormatUncovered returns list of uncovered changed lines in "file:line" format.
+//
+// File paths are relative to source directory.
+func (r *DiffReport) FormatUncovered(sourceDir string) string {
+	fileNames := make([]string, 0, len(r.Uncovered))
+	for k := range r.Uncovered {
+		fileNames = append(fileNames, k)
+	}
+
+	sort.Strings(fileNames)
+	b := strings.Builder{}
+	for _, fileName := range fileNames {
+		name := relPath(sourceDir, fileName)
+		for _, lr := range r.Uncovered[fileName] {
+			_, _ = fmt.Fprintf(&b, "  - %s:%s\n", name, lr)
+		}
+	}
+
+	return b.String()
+}
+
+// CheckCoverage checks if changed statements coverage satisfies threshold
+func (r *DiffReport) CheckCoverage(threshold float64) error {
+	if r.Total == 0 {
+		return nil
+	}
+
+	coverage := r.Percentage()
+	if coverage < threshold {
+		return fmt.Errorf("code coverage of changed statements is below %.2f%% (got %.2f%%)", threshold, coverage)
+	}
+
+	return nil
+}
+
+func linesChanged(changed map[int]struct{}, start, end int) bool {
+	if start == 0 {
+		return false
+	}
+
+	for l := start; l <= end; l++ {
+		if _, ok := changed[l]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// lineRanges groups sequential line numbers into ranges
+func lineRanges(lines map[int]struct{}) []LineRange {
+	nums := make([]int, 0, len(lines))
+	for l := range lines {
+		nums = append(nums, l)
+	}
+
+	sort.Ints(nums)
+	ranges := make([]LineRange, 0, len(nums))
+	for _, n := range nums {
+		if last := len(ranges) - 1; last >= 0 && ranges[last].End+1 == n {
+			ranges[last].End = n
+			continue
+		}
+
+		ranges = append(ranges, LineRange{Start: n, End: n})
+	}
+
+	return ranges
+}
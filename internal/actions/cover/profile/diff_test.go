@@ -0,0 +1,58 @@
+package profile
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateDiff(t *testing.T) {
+	pkgs, dir := newExportPackages(t)
+	fileName := filepath.Join(dir, "foo.go")
+
+	cases := map[string]struct {
+		changes   ChangedLines
+		coverage  Coverage
+		uncovered string
+	}{
+		"no changes": {},
+		"changed covered statement": {
+			changes:  ChangedLines{fileName: {5: {}}},
+			coverage: Coverage{Total: 1, Reached: 1},
+		},
+		"changed uncovered function": {
+			changes:   ChangedLines{fileName: {4: {}, 8: {}, 9: {}, 10: {}}},
+			coverage:  Coverage{Total: 2, Reached: 1},
+			uncovered: "  - foo.go:9\n",
+		},
+		"ignore unknown files": {
+			changes: ChangedLines{filepath.Join(dir, "bar.go"): {4: {}}},
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			r := CreateDiff(pkgs, c.changes)
+			assert.Equal(t, c.coverage, r.Coverage)
+			assert.Equal(t, c.uncovered, r.FormatUncovered(dir))
+		})
+	}
+}
+
+func TestDiffReport_CheckCoverage(t *testing.T) {
+	r := DiffReport{Coverage: Coverage{Total: 4, Reached: 1}}
+	assert.EqualError(t, r.CheckCoverage(50), "code coverage of changed statements is below 50.00% (got 25.00%)")
+	assert.NoError(t, r.CheckCoverage(25))
+
+	empty := DiffReport{}
+	assert.NoError(t, empty.CheckCoverage(100), "report without changed statements should pass")
+}
+
+func TestLineRanges(t *testing.T) {
+	lines := map[int]struct{}{1: {}, 2: {}, 3: {}, 7: {}, 9: {}, 10: {}}
+	ranges := lineRanges(lines)
+	assert.Equal(t, []LineRange{{1, 3}, {7, 7}, {9, 10}}, ranges)
+	assert.Equal(t, "1-3", ranges[0].String())
+	assert.Equal(t, "7", ranges[1].String())
+}
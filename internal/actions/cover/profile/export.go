@@ -0,0 +1,79 @@
+package profile
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// FormatCobertura is Cobertura XML report format
+	FormatCobertura = "cobertura"
+
+	// FormatLCOV is LCOV tracefile format
+	FormatLCOV = "lcov"
+
+	// FormatJSON is JSON summary format
+	FormatJSON = "json"
+)
+
+// ExportFormats is list of supported report export formats
+var ExportFormats = []string{FormatCobertura, FormatLCOV, FormatJSON}
+
+// Export writes report in specified format.
+//
+// Source file paths are written relative to sourceDir.
+func (r *Report) Export(w io.Writer, format, sourceDir string) error {
+	switch format {
+	case FormatCobertura:
+		return r.writeCobertura(w, sourceDir)
+	case FormatLCOV:
+		return r.writeLCOV(w, sourceDir)
+	case FormatJSON:
+		return r.writeJSON(w, sourceDir)
+	default:
+		return fmt.Errorf("unsupported report format '%s' (expected %s)", format, strings.Join(ExportFormats, ", "))
+	}
+}
+
+// relPath returns file path relative to source directory.
+//
+// Absolute path is returned if file is outside of source directory.
+func relPath(sourceDir, fileName string) string {
+	if sourceDir == "" {
+		return filepath.ToSlash(fileName)
+	}
+
+	rel, err := filepath.Rel(sourceDir, fileName)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(fileName)
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+// rate returns covered items ratio
+func rate(covered, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return float64(covered) / float64(total)
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// lineCoverage returns count of total and covered lines
+func lineCoverage(lines []LineHits) (total, covered int) {
+	for _, l := range lines {
+		if l.Hits > 0 {
+			covered++
+		}
+	}
+
+	return len(lines), covered
+}
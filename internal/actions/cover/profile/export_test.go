@@ -0,0 +1,169 @@
+package profile
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/axw/gocov"
+	"github.com/axw/gocov/gocovutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const exportSource = `package foo
+
+func Foo() int {
+	a := 1
+	return a
+}
+
+func Bar() {
+	println("bar")
+}
+`
+
+// newExportPackages creates profile for exportSource with called "Foo" function
+func newExportPackages(t *testing.T) (gocovutil.Packages, string) {
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "foo.go")
+	require.NoError(t, os.WriteFile(fileName, []byte(exportSource), 0644))
+
+	stmt := func(s string, reached int64) *gocov.Statement {
+		start := strings.Index(exportSource, s)
+		return &gocov.Statement{Start: start, End: start + len(s), Reached: reached}
+	}
+
+	pkgs := gocovutil.Packages{
+		{
+			Name: "example.com/foo",
+			Functions: []*gocov.Function{
+				{
+					Name:       "Foo",
+					File:       fileName,
+					Start:      strings.Index(exportSource, "func Foo"),
+					Statements: []*gocov.Statement{stmt("a := 1", 2), stmt("return a", 2)},
+				},
+				{
+					Name:       "Bar",
+					File:       fileName,
+					Start:      strings.Index(exportSource, "func Bar"),
+					Statements: []*gocov.Statement{stmt(`println("bar")`, 0)},
+				},
+			},
+		},
+	}
+
+	return pkgs, dir
+}
+
+func newExportReport(t *testing.T) (Report, string) {
+	pkgs, dir := newExportPackages(t)
+	return Create(pkgs), dir
+}
+
+func TestReport_ExportLCOV(t *testing.T) {
+	r, dir := newExportReport(t)
+	buff := &bytes.Buffer{}
+	require.NoError(t, r.Export(buff, FormatLCOV, dir))
+
+	expected := `TN:
+SF:foo.go
+FN:3,Foo
+FN:8,Bar
+FNDA:2,Foo
+FNDA:0,Bar
+FNF:2
+FNH:1
+DA:4,2
+DA:5,2
+DA:9,0
+LF:3
+LH:2
+end_of_record
+`
+	assert.Equal(t, expected, buff.String())
+}
+
+func TestReport_ExportJSON(t *testing.T) {
+	r, dir := newExportReport(t)
+	buff := &bytes.Buffer{}
+	require.NoError(t, r.Export(buff, FormatJSON, dir))
+
+	var got jsonReport
+	require.NoError(t, json.Unmarshal(buff.Bytes(), &got))
+	expected := jsonReport{
+		jsonCoverage: jsonCoverage{Statements: 3, Covered: 2, Percentage: 66.67},
+		Packages: []jsonPackage{
+			{
+				Name:         "example.com/foo",
+				jsonCoverage: jsonCoverage{Statements: 3, Covered: 2, Percentage: 66.67},
+				Functions: []jsonFunction{
+					{Name: "Foo", File: "foo.go", Line: 3, jsonCoverage: jsonCoverage{Statements: 2, Covered: 2, Percentage: 100}},
+					{Name: "Bar", File: "foo.go", Line: 8, jsonCoverage: jsonCoverage{Statements: 1}},
+				},
+			},
+		},
+	}
+	assert.Equal(t, expected, got)
+}
+
+func TestReport_ExportCobertura(t *testing.T) {
+	r, dir := newExportReport(t)
+	buff := &bytes.Buffer{}
+	require.NoError(t, r.Export(buff, FormatCobertura, dir))
+
+	var got coberturaCoverage
+	require.NoError(t, xml.Unmarshal(buff.Bytes(), &got))
+	assert.Equal(t, []string{dir}, got.Sources)
+	assert.Equal(t, 3, got.LinesValid)
+	assert.Equal(t, 2, got.LinesCovered)
+	require.Len(t, got.Packages, 1)
+	require.Len(t, got.Packages[0].Classes, 1)
+
+	class := got.Packages[0].Classes[0]
+	assert.Equal(t, "foo.go", class.FileName)
+	assert.Equal(t, []coberturaLine{{Number: 4, Hits: 2}, {Number: 5, Hits: 2}, {Number: 9}}, class.Lines)
+	require.Len(t, class.Methods, 2)
+	assert.Equal(t, "Foo", class.Methods[0].Name)
+	assert.Equal(t, 1.0, class.Methods[0].LineRate)
+}
+
+func TestReport_Export_UnsupportedFormat(t *testing.T) {
+	r, dir := newExportReport(t)
+	err := r.Export(&bytes.Buffer{}, "html", dir)
+	assert.EqualError(t, err, "unsupported report format 'html' (expected cobertura, lcov, json)")
+}
+
+func TestRelPath(t *testing.T) {
+	cases := map[string]struct {
+		dir      string
+		file     string
+		expected string
+	}{
+		"file inside source dir": {
+			dir:      "/project",
+			file:     "/project/pkg/foo.go",
+			expected: "pkg/foo.go",
+		},
+		"file outside source dir": {
+			dir:      "/project",
+			file:     "/other/foo.go",
+			expected: "/other/foo.go",
+		},
+		"no source dir": {
+			file:     "/project/foo.go",
+			expected: "/project/foo.go",
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			assert.Equal(t, c.expected, relPath(c.dir, c.file))
+		})
+	}
+}
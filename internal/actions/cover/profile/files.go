@@ -0,0 +1,150 @@
+package profile
+
+import (
+	"os"
+	"sort"
+
+	"github.com/axw/gocov"
+)
+
+// LineHits is execution count of statements started at source line
+type LineHits struct {
+	// Line is line number
+	Line int
+
+	// Hits is execution count
+	Hits int64
+}
+
+// FunctionReport is function coverage report with source location
+type FunctionReport struct {
+	Coverage
+
+	// Name is function name
+	Name string
+
+	// Line is function start line
+	Line int
+
+	// Lines contains execution count of function lines
+	Lines []LineHits
+
+	// Uncovered contains line ranges of not executed statements
+	Uncovered []LineRange
+}
+
+// FileReport is source file coverage report
+type FileReport struct {
+	Coverage
+
+	// Functions contains file functions in order of declaration
+	Functions []*FunctionReport
+}
+
+// Lines returns execution count of file lines sorted by line number
+func (f *FileReport) Lines() []LineHits {
+	hits := make(map[int]int64)
+	for _, fn := range f.Functions {
+		for _, l := range fn.Lines {
+			if h, ok := hits[l.Line]; !ok || l.Hits > h {
+				hits[l.Line] = l.Hits
+			}
+		}
+	}
+
+	return sortedLines(hits)
+}
+
+// FileNames returns sorted names of package source files
+func (p *PackageReport) FileNames() []string {
+	out := make([]string, 0, len(p.Files))
+	for k := range p.Files {
+		out = append(out, k)
+	}
+
+	sort.Strings(out)
+	return out
+}
+
+// lineIndex converts file offsets to line numbers
+type lineIndex map[string][]int
+
+// line returns line number of offset in file.
+//
+// Returns zero if file is not available.
+func (idx lineIndex) line(fileName string, offset int) int {
+	offsets, ok := idx[fileName]
+	if !ok {
+		if data, err := os.ReadFile(fileName); err == nil {
+			offsets = lineOffsets(data)
+		}
+
+		idx[fileName] = offsets
+	}
+
+	if len(offsets) == 0 {
+		return 0
+	}
+
+	return sort.Search(len(offsets), func(i int) bool {
+		return offsets[i] > offset
+	})
+}
+
+func (idx lineIndex) functionReport(fn *gocov.Function, c Coverage) *FunctionReport {
+	r := &FunctionReport{
+		Coverage: c,
+		Name:     fn.Name,
+		Line:     idx.line(fn.File, fn.Start),
+	}
+
+	if r.Line == 0 {
+		return r
+	}
+
+	hits := make(map[int]int64, len(fn.Statements))
+	uncovered := make(map[int]struct{})
+	for _, s := range fn.Statements {
+		line := idx.line(fn.File, s.Start)
+		if h, ok := hits[line]; !ok || s.Reached > h {
+			hits[line] = s.Reached
+		}
+
+		if s.Reached > 0 {
+			continue
+		}
+
+		for l := line; l <= idx.line(fn.File, s.End); l++ {
+			uncovered[l] = struct{}{}
+		}
+	}
+
+	r.Lines = sortedLines(hits)
+	r.Uncovered = lineRanges(uncovered)
+	return r
+}
+
+// lineOffsets returns offsets of each line start
+func lineOffsets(data []byte) []int {
+	offsets := []int{0}
+	for i, b := range data {
+		if b == '\n' {
+			offsets = append(offsets, i+1)
+		}
+	}
+
+	return offsets
+}
+
+func sortedLines(hits map[int]int64) []LineHits {
+	lines := make([]LineHits, 0, len(hits))
+	for l, h := range hits {
+		lines = append(lines, LineHits{Line: l, Hits: h})
+	}
+
+	sort.Slice(lines, func(i, j int) bool {
+		return lines[i].Line < lines[j].Line
+	})
+
+	return lines
+}
@@ -0,0 +1,154 @@
+package profile
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/axw/gocov"
+	"github.com/axw/gocov/gocovutil"
+)
+
+// recursiveSuffix is package pattern suffix which matches package and all its sub-packages
+const recursiveSuffix = "/..."
+
+// ValidatePattern checks if package or file glob pattern is valid
+func ValidatePattern(pattern string) error {
+	if _, err := path.Match(strings.TrimSuffix(pattern, recursiveSuffix), ""); err != nil {
+		return fmt.Errorf("invalid pattern '%s': %s", pattern, err)
+	}
+
+	return nil
+}
+
+// MatchPackage checks if package import path matches glob pattern.
+//
+// Pattern with "/..." suffix also matches all sub-packages.
+func MatchPackage(pattern, pkgName string) bool {
+	if prefix := strings.TrimSuffix(pattern, recursiveSuffix); prefix != pattern {
+		if ok, _ := path.Match(prefix, pkgName); ok {
+			return true
+		}
+
+		// match parent package of sub-package
+		for dir := path.Dir(pkgName); dir != "." && dir != "/"; dir = path.Dir(dir) {
+			if ok, _ := path.Match(prefix, dir); ok {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	ok, _ := path.Match(pattern, pkgName)
+	return ok
+}
+
+// excluded checks if function belongs to file or package excluded by patterns.
+//
+// Pattern is matched against file name, package import path and each package path element,
+// so "*_mock.go" excludes mock files and "mocks" excludes all "mocks" packages.
+func excluded(patterns []string, pkgName, fileName string) bool {
+	baseName := filepath.Base(fileName)
+	elems := strings.Split(pkgName, "/")
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, baseName); ok {
+			return true
+		}
+
+		if MatchPackage(p, pkgName) {
+			return true
+		}
+
+		for _, e := range elems {
+			if ok, _ := path.Match(p, e); ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Exclude removes functions of files and packages matched by glob patterns.
+//
+// Packages without functions left are removed from result.
+func Exclude(pkgs gocovutil.Packages, patterns []string) gocovutil.Packages {
+	if len(patterns) == 0 {
+		return pkgs
+	}
+
+	out := make(gocovutil.Packages, 0, len(pkgs))
+	for _, pkg := range pkgs {
+		fns := make([]*gocov.Function, 0, len(pkg.Functions))
+		for _, fn := range pkg.Functions {
+			if !excluded(patterns, pkg.Name, fn.File) {
+				fns = append(fns, fn)
+			}
+		}
+
+		if len(fns) == 0 {
+			continue
+		}
+
+		out = append(out, &gocov.Package{Name: pkg.Name, Functions: fns})
+	}
+
+	return out
+}
+
+// Thresholds is a set of minimal coverage percentage by package pattern
+type Thresholds map[string]float64
+
+// threshold returns threshold of the most specific (longest) pattern which matches package
+func (t Thresholds) threshold(pkgName string) (float64, bool) {
+	pattern, found := "", false
+	for p := range t {
+		if !MatchPackage(p, pkgName) {
+			continue
+		}
+
+		if !found || len(p) > len(pattern) || (len(p) == len(pattern) && p < pattern) {
+			pattern, found = p, true
+		}
+	}
+
+	return t[pattern], found
+}
+
+// CheckPackagesCoverage checks if each package satisfies its coverage threshold.
+//
+// Returned error contains all packages with insufficient coverage.
+func (r *Report) CheckPackagesCoverage(thresholds Thresholds) error {
+	if len(thresholds) == 0 {
+		return nil
+	}
+
+	names := r.Packages.Names()
+	sort.Strings(names)
+
+	b := strings.Builder{}
+	failed := 0
+	for _, name := range names {
+		threshold, ok := thresholds.threshold(name)
+		if !ok {
+			continue
+		}
+
+		coverage := r.Packages[name].Percentage()
+		if coverage >= threshold {
+			continue
+		}
+
+		failed++
+		_, _ = fmt.Fprintf(&b, "\n  - %s: %.2f%% (expected %.2f%%)", name, coverage, threshold)
+	}
+
+	if failed == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("code coverage of %d package(s) is below threshold:%s", failed, b.String())
+}
@@ -0,0 +1,132 @@
+package profile
+
+import (
+	"testing"
+
+	"github.com/axw/gocov"
+	"github.com/axw/gocov/gocovutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchPackage(t *testing.T) {
+	cases := map[string]struct {
+		pattern string
+		pkg     string
+		match   bool
+	}{
+		"exact match": {
+			pattern: "example.com/foo",
+			pkg:     "example.com/foo",
+			match:   true,
+		},
+		"glob match": {
+			pattern: "example.com/*/api",
+			pkg:     "example.com/foo/api",
+			match:   true,
+		},
+		"glob doesn't match sub-packages": {
+			pattern: "example.com/*",
+			pkg:     "example.com/foo/bar",
+		},
+		"recursive pattern matches package": {
+			pattern: "example.com/foo/...",
+			pkg:     "example.com/foo",
+			match:   true,
+		},
+		"recursive pattern matches sub-packages": {
+			pattern: "example.com/foo/...",
+			pkg:     "example.com/foo/bar/baz",
+			match:   true,
+		},
+		"recursive pattern doesn't match other packages": {
+			pattern: "example.com/foo/...",
+			pkg:     "example.com/foobar",
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			assert.Equal(t, c.match, MatchPackage(c.pattern, c.pkg))
+		})
+	}
+}
+
+func TestExclude(t *testing.T) {
+	fn := func(name, file string) *gocov.Function {
+		return &gocov.Function{Name: name, File: file}
+	}
+
+	pkgs := gocovutil.Packages{
+		{
+			Name: "example.com/foo",
+			Functions: []*gocov.Function{
+				fn("Foo", "/src/foo/foo.go"),
+				fn("Mock", "/src/foo/foo_mock.go"),
+				fn("Message", "/src/foo/foo.pb.go"),
+			},
+		},
+		{
+			Name:      "example.com/foo/exprmock",
+			Functions: []*gocov.Function{fn("Parser", "/src/foo/exprmock/parser.go")},
+		},
+		{
+			Name:      "example.com/bar",
+			Functions: []*gocov.Function{fn("Bar", "/src/bar/bar.go")},
+		},
+	}
+
+	got := Exclude(pkgs, []string{"*_mock.go", "*.pb.go", "exprmock", "example.com/bar"})
+	expected := gocovutil.Packages{
+		{
+			Name:      "example.com/foo",
+			Functions: []*gocov.Function{fn("Foo", "/src/foo/foo.go")},
+		},
+	}
+	assert.Equal(t, expected, got)
+	assert.Equal(t, pkgs, Exclude(pkgs, nil))
+}
+
+func TestReport_CheckPackagesCoverage(t *testing.T) {
+	r := Report{
+		Packages: Packages{
+			"example.com/foo":         cov2report(40),
+			"example.com/foo/bar":     cov2report(70),
+			"example.com/foo/bar/baz": cov2report(90),
+			"example.com/legacy":      cov2report(10),
+			"example.com/other":       cov2report(20),
+		},
+	}
+
+	cases := map[string]struct {
+		thresholds Thresholds
+		err        string
+	}{
+		"pass without thresholds": {},
+		"pass when thresholds satisfied": {
+			thresholds: Thresholds{"example.com/foo/bar/...": 70},
+		},
+		"use most specific pattern": {
+			thresholds: Thresholds{
+				"example.com/...":         50,
+				"example.com/legacy":      5,
+				"example.com/foo/bar/baz": 95,
+			},
+			err: "code coverage of 3 package(s) is below threshold:\n" +
+				"  - example.com/foo: 40.00% (expected 50.00%)\n" +
+				"  - example.com/foo/bar/baz: 90.00% (expected 95.00%)\n" +
+				"  - example.com/other: 20.00% (expected 50.00%)",
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			err := r.CheckPackagesCoverage(c.thresholds)
+			if c.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.EqualError(t, err, c.err)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package profile
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FunctionsBelow returns functions with coverage below threshold
+// grouped by file name in order of declaration.
+//
+// Functions without statements are ignored.
+func (r *Report) FunctionsBelow(threshold float64) map[string][]*FunctionReport {
+	out := make(map[string][]*FunctionReport)
+	for _, pkg := range r.Packages {
+		for fileName, f := range pkg.Files {
+			for _, fn := range f.Functions {
+				if fn.Total == 0 || fn.Percentage() >= threshold {
+					continue
+				}
+
+				out[fileName] = append(out[fileName], fn)
+			}
+		}
+	}
+
+	return out
+}
+
+// FormatFunctions returns list of functions with coverage below threshold
+// in "file:line" format with uncovered line ranges.
+//
+// File paths are relative to source directory.
+func (r *Report) FormatFunctions(sourceDir string, threshold float64) string {
+	fns := r.FunctionsBelow(threshold)
+	fileNames := make([]string, 0, len(fns))
+	for k := range fns {
+		fileNames = append(fileNames, k)
+	}
+
+	sort.Strings(fileNames)
+	b := strings.Builder{}
+	for _, fileName := range fileNames {
+		name := relPath(sourceDir, fileName)
+		for _, fn := range fns[fileName] {
+			_, _ = fmt.Fprintf(&b, "  - %s:%d %s: %.2f%%", name, fn.Line, fn.Name, fn.Percentage())
+			if len(fn.Uncovered) > 0 {
+				ranges := make([]string, 0, len(fn.Uncovered))
+				for _, lr := range fn.Uncovered {
+					ranges = append(ranges, lr.String())
+				}
+
+				_, _ = fmt.Fprintf(&b, " (uncovered lines: %s)", strings.Join(ranges, ", "))
+			}
+
+			b.WriteString("\n")
+		}
+	}
+
+	return b.String()
+}
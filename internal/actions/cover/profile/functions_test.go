@@ -0,0 +1,77 @@
+package profile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/axw/gocov"
+	"github.com/axw/gocov/gocovutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const functionsSource = `package foo
+
+func Foo(ok bool) int {
+	if ok {
+		return 1
+	}
+
+	println("a",
+		"b")
+	return 0
+}
+
+func Bar() {
+	println("bar")
+}
+
+func Baz() {}
+`
+
+func TestReport_FormatFunctions(t *testing.T) {
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "foo.go")
+	require.NoError(t, os.WriteFile(fileName, []byte(functionsSource), 0644))
+
+	stmt := func(s string, reached int64) *gocov.Statement {
+		start := strings.Index(functionsSource, s)
+		return &gocov.Statement{Start: start, End: start + len(s), Reached: reached}
+	}
+
+	r := Create(gocovutil.Packages{
+		{
+			Name: "example.com/foo",
+			Functions: []*gocov.Function{
+				{
+					Name:  "Foo",
+					File:  fileName,
+					Start: strings.Index(functionsSource, "func Foo"),
+					Statements: []*gocov.Statement{
+						stmt("if ok {\n\t\treturn 1\n\t}", 1),
+						stmt("return 1", 1),
+						stmt("println(\"a\",\n\t\t\"b\")", 0),
+						stmt("return 0", 0),
+					},
+				},
+				{
+					Name:       "Bar",
+					File:       fileName,
+					Start:      strings.Index(functionsSource, "func Bar"),
+					Statements: []*gocov.Statement{stmt(`println("bar")`, 1)},
+				},
+				{
+					Name:  "Baz",
+					File:  fileName,
+					Start: strings.Index(functionsSource, "func Baz"),
+				},
+			},
+		},
+	})
+
+	assert.Equal(t, "  - foo.go:3 Foo: 50.00% (uncovered lines: 8-10)\n", r.FormatFunctions(dir, 80))
+	assert.Equal(t, "  - foo.go:3 Foo: 50.00% (uncovered lines: 8-10)\n  - foo.go:13 Bar: 100.00%\n", r.FormatFunctions(dir, 101))
+	assert.Empty(t, r.FormatFunctions(dir, 50))
+}
@@ -0,0 +1,72 @@
+package profile
+
+import (
+	"encoding/json"
+	"io"
+)
+
+type jsonCoverage struct {
+	Statements int     `json:"statements"`
+	Covered    int     `json:"covered"`
+	Percentage float64 `json:"percentage"`
+}
+
+type jsonFunction struct {
+	Name string `json:"name"`
+	File string `json:"file"`
+	Line int    `json:"line,omitempty"`
+	jsonCoverage
+}
+
+type jsonPackage struct {
+	Name string `json:"name"`
+	jsonCoverage
+	Functions []jsonFunction `json:"functions"`
+}
+
+type jsonReport struct {
+	jsonCoverage
+	Packages []jsonPackage `json:"packages"`
+}
+
+func newJSONCoverage(c Coverage) jsonCoverage {
+	return jsonCoverage{
+		Statements: c.Total,
+		Covered:    c.Reached,
+		Percentage: round(c.Percentage()),
+	}
+}
+
+// writeJSON writes report summary in JSON format
+func (r *Report) writeJSON(w io.Writer, sourceDir string) error {
+	out := jsonReport{
+		jsonCoverage: newJSONCoverage(r.Coverage),
+		Packages:     make([]jsonPackage, 0, len(r.Packages)),
+	}
+
+	for _, pkgName := range r.Packages.Sort(ByName, false) {
+		pkg := r.Packages[pkgName]
+		p := jsonPackage{
+			Name:         pkgName,
+			jsonCoverage: newJSONCoverage(pkg.Coverage),
+			Functions:    make([]jsonFunction, 0, len(pkg.Functions)),
+		}
+
+		for _, fileName := range pkg.FileNames() {
+			for _, fn := range pkg.Files[fileName].Functions {
+				p.Functions = append(p.Functions, jsonFunction{
+					Name:         fn.Name,
+					File:         relPath(sourceDir, fileName),
+					Line:         fn.Line,
+					jsonCoverage: newJSONCoverage(fn.Coverage),
+				})
+			}
+		}
+
+		out.Packages = append(out.Packages, p)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
@@ -0,0 +1,52 @@
+package profile
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// writeLCOV writes report in LCOV tracefile format
+func (r *Report) writeLCOV(w io.Writer, sourceDir string) error {
+	bw := bufio.NewWriter(w)
+	for _, pkgName := range r.Packages.Sort(ByName, false) {
+		pkg := r.Packages[pkgName]
+		for _, fileName := range pkg.FileNames() {
+			writeLCOVRecord(bw, relPath(sourceDir, fileName), pkg.Files[fileName])
+		}
+	}
+
+	return bw.Flush()
+}
+
+func writeLCOVRecord(w io.Writer, fileName string, f *FileReport) {
+	_, _ = fmt.Fprintf(w, "TN:\nSF:%s\n", fileName)
+
+	fnHit := 0
+	for _, fn := range f.Functions {
+		_, _ = fmt.Fprintf(w, "FN:%d,%s\n", fn.Line, fn.Name)
+	}
+
+	for _, fn := range f.Functions {
+		var hits int64
+		if len(fn.Lines) > 0 {
+			hits = fn.Lines[0].Hits
+		}
+
+		if hits > 0 {
+			fnHit++
+		}
+
+		_, _ = fmt.Fprintf(w, "FNDA:%d,%s\n", hits, fn.Name)
+	}
+
+	_, _ = fmt.Fprintf(w, "FNF:%d\nFNH:%d\n", len(f.Functions), fnHit)
+
+	lines := f.Lines()
+	for _, l := range lines {
+		_, _ = fmt.Fprintf(w, "DA:%d,%d\n", l.Line, l.Hits)
+	}
+
+	total, covered := lineCoverage(lines)
+	_, _ = fmt.Fprintf(w, "LF:%d\nLH:%d\nend_of_record\n", total, covered)
+}
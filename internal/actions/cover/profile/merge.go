@@ -0,0 +1,76 @@
+package profile
+
+import (
+	"sort"
+
+	"golang.org/x/tools/cover"
+)
+
+// blockKey identifies profile block by its source position
+type blockKey struct {
+	startLine, startCol int
+	endLine, endCol     int
+	numStmt             int
+}
+
+func newBlockKey(b cover.ProfileBlock) blockKey {
+	return blockKey{
+		startLine: b.StartLine,
+		startCol:  b.StartCol,
+		endLine:   b.EndLine,
+		endCol:    b.EndCol,
+		numStmt:   b.NumStmt,
+	}
+}
+
+// MergeProfiles reads cover profiles and merges them into one profile per source file.
+//
+// Execution counts of the same blocks from different profiles are summed.
+func MergeProfiles(filenames ...string) ([]*cover.Profile, error) {
+	merged := make(map[string]*cover.Profile)
+	counts := make(map[string]map[blockKey]int)
+	for _, fileName := range filenames {
+		profiles, err := cover.ParseProfiles(fileName)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range profiles {
+			m, ok := merged[p.FileName]
+			if !ok {
+				m = &cover.Profile{FileName: p.FileName, Mode: p.Mode}
+				merged[p.FileName] = m
+				counts[p.FileName] = make(map[blockKey]int, len(p.Blocks))
+			}
+
+			for _, b := range p.Blocks {
+				k := newBlockKey(b)
+				if _, ok := counts[p.FileName][k]; !ok {
+					m.Blocks = append(m.Blocks, b)
+				}
+
+				counts[p.FileName][k] += b.Count
+			}
+		}
+	}
+
+	out := make([]*cover.Profile, 0, len(merged))
+	for fileName, p := range merged {
+		for i, b := range p.Blocks {
+			p.Blocks[i].Count = counts[fileName][newBlockKey(b)]
+		}
+
+		sort.Slice(p.Blocks, func(i, j int) bool {
+			bi, bj := p.Blocks[i], p.Blocks[j]
+			return bi.StartLine < bj.StartLine || (bi.StartLine == bj.StartLine && bi.StartCol < bj.StartCol)
+		})
+
+		out = append(out, p)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].FileName < out[j].FileName
+	})
+
+	return out, nil
+}
@@ -0,0 +1,47 @@
+package profile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/cover"
+)
+
+func TestMergeProfiles(t *testing.T) {
+	dir := t.TempDir()
+	unit := filepath.Join(dir, "unit.out")
+	e2e := filepath.Join(dir, "e2e.out")
+	require.NoError(t, os.WriteFile(unit, []byte(`mode: set
+example.com/foo/foo.go:3.16,5.2 1 1
+example.com/foo/foo.go:7.12,9.2 1 0
+`), 0644))
+	require.NoError(t, os.WriteFile(e2e, []byte(`mode: set
+example.com/foo/foo.go:7.12,9.2 1 1
+example.com/foo/foo.go:3.16,5.2 1 0
+example.com/bar/bar.go:3.12,5.2 2 1
+`), 0644))
+
+	got, err := MergeProfiles(unit, e2e)
+	require.NoError(t, err)
+	assert.Equal(t, []*cover.Profile{
+		{
+			FileName: "example.com/bar/bar.go",
+			Mode:     "set",
+			Blocks:   []cover.ProfileBlock{{StartLine: 3, StartCol: 12, EndLine: 5, EndCol: 2, NumStmt: 2, Count: 1}},
+		},
+		{
+			FileName: "example.com/foo/foo.go",
+			Mode:     "set",
+			Blocks: []cover.ProfileBlock{
+				{StartLine: 3, StartCol: 16, EndLine: 5, EndCol: 2, NumStmt: 1, Count: 1},
+				{StartLine: 7, StartCol: 12, EndLine: 9, EndCol: 2, NumStmt: 1, Count: 1},
+			},
+		},
+	}, got)
+
+	_, err = MergeProfiles(filepath.Join(dir, "missing.out"))
+	assert.Error(t, err)
+}
@@ -110,6 +110,9 @@ func (r *Report) FormatSimple(orderProp string, desc bool) string {
 type PackageReport struct {
 	Coverage
 	Functions map[string]*Coverage
+
+	// Files contains coverage of package source files
+	Files map[string]*FileReport
 }
 
 func (p *PackageReport) names() []string {
@@ -139,8 +142,9 @@ func (p *PackageReport) Sort(by string, desc bool) []string {
 // Create creates a new report from GoCov profile
 func Create(pkgs gocovutil.Packages) (r Report) {
 	r.Packages = make(map[string]*PackageReport, len(pkgs))
+	idx := make(lineIndex)
 	for _, pkg := range pkgs {
-		cov := pkgCoverage(pkg)
+		cov := pkgCoverage(pkg, idx)
 		r.add(cov.Coverage)
 		r.Packages[pkg.Name] = cov
 	}
@@ -148,21 +152,32 @@ func Create(pkgs gocovutil.Packages) (r Report) {
 	return r
 }
 
-func pkgCoverage(pkg *gocov.Package) *PackageReport {
+func pkgCoverage(pkg *gocov.Package, idx lineIndex) *PackageReport {
 	report := &PackageReport{}
 	if len(pkg.Functions) == 0 {
 		return report
 	}
 
 	fns := make(map[string]*Coverage, len(pkg.Functions))
+	files := make(map[string]*FileReport)
 	for _, fn := range pkg.Functions {
 		c := Coverage{}
 		c.Total, c.Reached = fnCoverage(fn)
 		report.Coverage.add(c)
 		fns[fn.Name] = &c
+
+		f, ok := files[fn.File]
+		if !ok {
+			f = &FileReport{}
+			files[fn.File] = f
+		}
+
+		f.Coverage.add(c)
+		f.Functions = append(f.Functions, idx.functionReport(fn, c))
 	}
 
 	report.Functions = fns
+	report.Files = files
 	return report
 }
 
@@ -6,7 +6,9 @@ import (
 )
 
 const (
+	actionRun    = "run"
 	actionOutput = "output"
+	actionPass   = "pass"
 	actionSkip   = "skip"
 	actionFail   = "fail"
 )
@@ -14,6 +16,9 @@ const (
 // ignoredLines contains lines that should be excluded from lines
 var ignoredLines = []string{"=== RUN", "--- FAIL", "coverage:", "FAIL"}
 
+// servicePrefixes contains prefixes of "go test" service lines not related to test output
+var servicePrefixes = []string{"=== PAUSE", "=== CONT", "=== NAME", "--- PASS", "--- SKIP", "ok  \t"}
+
 // Line represents JSON line from "go test" tool's lines
 type Line struct {
 	Time    string
@@ -21,6 +26,7 @@ type Line struct {
 	Package string
 	Test    string
 	Output  string
+	Elapsed float64
 }
 
 // Lines is set of lines
@@ -114,3 +120,18 @@ func lineIgnored(data string) bool {
 
 	return false
 }
+
+func serviceLine(data string) bool {
+	data = strings.TrimLeft(data, " ")
+	if strings.TrimSpace(data) == "PASS" {
+		return true
+	}
+
+	for _, s := range servicePrefixes {
+		if strings.HasPrefix(data, s) {
+			return true
+		}
+	}
+
+	return false
+}
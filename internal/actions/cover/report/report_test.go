@@ -68,3 +68,53 @@ func TestLines_SkippedPackages(t *testing.T) {
 	skipped := lines.SkippedPackages()
 	assert.NotEmpty(t, skipped)
 }
+
+func TestLines_Results(t *testing.T) {
+	f, err := os.Open(fixturePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+	lines := make(Lines, 0)
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		if err := lines.AppendData(s.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var pkg *PackageResult
+	for _, p := range lines.Results() {
+		if p.Name == "github.com/go-gilbert/gilbert/actions/cover" {
+			pkg = p
+		}
+	}
+
+	if pkg == nil {
+		t.Fatal("package not found in results")
+	}
+
+	assert.True(t, pkg.Failed())
+	assert.Equal(t, 0.031, pkg.Elapsed)
+	assert.Equal(t, []string{"TestParamsValidate"}, pkg.FailedTests())
+
+	failed := pkg.FindTest("TestParamsValidate/should_validate_threshold_above_100")
+	if failed == nil {
+		t.Fatal("failed test not found in results")
+	}
+
+	assert.True(t, failed.Failed())
+	assert.True(t, failed.IsSubTest())
+	assert.Equal(t, []string{
+		`        params_test.go:48: error 'coverage threshold should be between 0 and 100 (got 101.000000)' should contain 'coverage threshold should be between 0 and 1001'`,
+	}, failed.Messages())
+
+	passed := pkg.FindTest("TestParamsValidate/should_validate_sort_type")
+	if passed == nil {
+		t.Fatal("passed test not found in results")
+	}
+
+	assert.False(t, passed.Failed())
+	assert.False(t, passed.Skipped())
+}
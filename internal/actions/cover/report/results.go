@@ -0,0 +1,168 @@
+package report
+
+import "strings"
+
+// TestResult is a result of a single test run
+type TestResult struct {
+	// Name is test name, including parent test names for subtests
+	Name string
+
+	// Status is test result status (pass, fail or skip)
+	Status string
+
+	// Elapsed is test duration in seconds
+	Elapsed float64
+
+	// Output is test output
+	Output []string
+}
+
+// Failed checks if test failed
+func (t TestResult) Failed() bool {
+	return t.Status == actionFail
+}
+
+// Skipped checks if test was skipped
+func (t TestResult) Skipped() bool {
+	return t.Status == actionSkip
+}
+
+// Messages returns test output without service lines
+func (t TestResult) Messages() []string {
+	return filterOutput(t.Output)
+}
+
+// IsSubTest checks if test is a subtest
+func (t TestResult) IsSubTest() bool {
+	return strings.Contains(t.Name, "/")
+}
+
+// PackageResult contains results of package tests
+type PackageResult struct {
+	// Name is package import path
+	Name string
+
+	// Status is package result status (pass, fail or skip)
+	Status string
+
+	// Elapsed is package test duration in seconds
+	Elapsed float64
+
+	// Tests contains package test results in order of start
+	Tests []*TestResult
+
+	// Output is package output not related to any test
+	Output []string
+}
+
+// Failed checks if package tests failed
+func (p PackageResult) Failed() bool {
+	return p.Status == actionFail
+}
+
+// Skipped checks if package has no tests
+func (p PackageResult) Skipped() bool {
+	return p.Status == actionSkip
+}
+
+// Messages returns package output without service lines
+func (p PackageResult) Messages() []string {
+	return filterOutput(p.Output)
+}
+
+// FailedTests returns names of failed top-level tests
+func (p PackageResult) FailedTests() []string {
+	names := make([]string, 0, len(p.Tests))
+	seen := make(map[string]struct{}, len(p.Tests))
+	for _, t := range p.Tests {
+		if !t.Failed() || t.IsSubTest() {
+			continue
+		}
+
+		if _, ok := seen[t.Name]; ok {
+			continue
+		}
+
+		seen[t.Name] = struct{}{}
+		names = append(names, t.Name)
+	}
+
+	return names
+}
+
+// FindTest returns last result of specified test
+func (p PackageResult) FindTest(name string) *TestResult {
+	for i := len(p.Tests) - 1; i >= 0; i-- {
+		if p.Tests[i].Name == name {
+			return p.Tests[i]
+		}
+	}
+
+	return nil
+}
+
+// Results groups test results by packages in order of appearance.
+//
+// Each test run is a separate result, so tests executed several times (e.g. with "-count" flag)
+// have several results.
+func (lns Lines) Results() []*PackageResult {
+	pkgs := make([]*PackageResult, 0)
+	pkgIndex := make(map[string]*PackageResult)
+	running := make(map[string]map[string]*TestResult)
+	for _, l := range lns {
+		if l.Package == "" {
+			continue
+		}
+
+		pkg, ok := pkgIndex[l.Package]
+		if !ok {
+			pkg = &PackageResult{Name: l.Package}
+			pkgIndex[l.Package] = pkg
+			running[l.Package] = make(map[string]*TestResult)
+			pkgs = append(pkgs, pkg)
+		}
+
+		if l.Test == "" {
+			switch l.Action {
+			case actionOutput:
+				pkg.Output = append(pkg.Output, l.Output)
+			case actionPass, actionFail, actionSkip:
+				pkg.Status = l.Action
+				pkg.Elapsed = l.Elapsed
+			}
+
+			continue
+		}
+
+		test, ok := running[l.Package][l.Test]
+		if !ok || l.Action == actionRun {
+			test = &TestResult{Name: l.Test}
+			running[l.Package][l.Test] = test
+			pkg.Tests = append(pkg.Tests, test)
+		}
+
+		switch l.Action {
+		case actionOutput:
+			test.Output = append(test.Output, l.Output)
+		case actionPass, actionFail, actionSkip:
+			test.Status = l.Action
+			test.Elapsed = l.Elapsed
+		}
+	}
+
+	return pkgs
+}
+
+func filterOutput(output []string) []string {
+	msgs := make([]string, 0, len(output))
+	for _, o := range output {
+		o = strings.TrimRight(o, "\n")
+		if strings.TrimSpace(o) == "" || lineIgnored(o) || serviceLine(o) {
+			continue
+		}
+
+		msgs = append(msgs, o)
+	}
+
+	return msgs
+}
@@ -0,0 +1,228 @@
+package gotest
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/go-gilbert/gilbert/internal/actions/cover/report"
+	"github.com/go-gilbert/gilbert/internal/log"
+	"github.com/go-gilbert/gilbert/internal/runner"
+	"github.com/go-gilbert/gilbert/internal/runner/job"
+	"github.com/go-gilbert/gilbert/internal/scope"
+	"github.com/go-gilbert/gilbert/internal/support/shell"
+)
+
+// actionBuildOutput is "go test" event which contains build errors output (Go 1.24+)
+const actionBuildOutput = "build-output"
+
+// Action is action handler
+type Action struct {
+	scope  *scope.Scope
+	params params
+}
+
+// Call calls an action
+func (a *Action) Call(ctx *job.RunContext, _ *runner.TaskRunner) error {
+	pkgs := make([]string, 0, len(a.params.Packages))
+	for _, pkg := range a.params.Packages {
+		val, err := a.scope.ExpandVariables(pkg)
+		if err != nil {
+			return err
+		}
+
+		pkgs = append(pkgs, val)
+	}
+
+	results, err := a.runTests(ctx, a.params.Run, pkgs)
+	if err != nil {
+		return err
+	}
+
+	run := newTestRun(results)
+	for attempt := 1; attempt <= a.params.Retries; attempt++ {
+		groups := run.retryGroups()
+		if len(groups) == 0 {
+			break
+		}
+
+		for _, g := range groups {
+			ctx.Log().Warnf("Re-running %d failed test(s) in %s (attempt %d of %d)", len(g.tests), g.pkg, attempt, a.params.Retries)
+			retried, err := a.runTests(ctx, runPattern(g.tests), []string{g.pkg})
+			if err != nil {
+				return err
+			}
+
+			run.merge(retried)
+		}
+	}
+
+	if a.params.JUnitReport != "" {
+		if err := writeJUnitReport(a.params.JUnitReport, run); err != nil {
+			return err
+		}
+
+		ctx.Log().Debugf("test: JUnit report saved to '%s'", a.params.JUnitReport)
+	}
+
+	a.printResults(ctx.Log(), run)
+	if count := run.failedPackages(); count > 0 {
+		return fmt.Errorf("tests failed in %d package(s)", count)
+	}
+
+	return nil
+}
+
+// runTests starts "go test" tool and collects test results
+func (a *Action) runTests(ctx *job.RunContext, runExpr string, pkgs []string) ([]*report.PackageResult, error) {
+	args := append([]string{"test"}, a.params.testFlags()...)
+	if runExpr != "" {
+		args = append(args, "-run="+runExpr)
+	}
+
+	args = append(args, pkgs...)
+	cmd := exec.CommandContext(ctx.Context(), "go", args...)
+	cmd.Dir = a.scope.Environment().ProjectDirectory
+	cmd.Stderr = ctx.Log().ErrorWriter()
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.Log().Debugf("test: exec '%s'", strings.Join(cmd.Args, " "))
+	if err = cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start 'go test' tool, %s", err)
+	}
+
+	lines, readErr := readLines(ctx.Log(), stdout)
+	err = cmd.Wait()
+	if ctxErr := ctx.Context().Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+
+	if readErr != nil {
+		return nil, fmt.Errorf("failed to read 'go test' output, %s", readErr)
+	}
+
+	results := lines.Results()
+	if err == nil {
+		return results, nil
+	}
+
+	// "go test" exits with non-zero code when tests fail, so it's not an error
+	// as long as failed packages are reported.
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && hasFailures(results) {
+		return results, nil
+	}
+
+	return nil, fmt.Errorf("test execution failed (%s)", shell.FormatExitError(err))
+}
+
+func (a *Action) printResults(l log.Logger, run *testRun) {
+	for _, p := range run.packages {
+		if isPackageError(p) {
+			l.Errorf("Package %s failed:", p.Name)
+			printMessages(l, p.Messages())
+			continue
+		}
+
+		for _, t := range run.failedTests(p) {
+			msgs := t.Messages()
+			if len(msgs) == 0 {
+				// parent tests of failed subtests usually have no own output
+				continue
+			}
+
+			l.Errorf("Test %s.%s failed:", p.Name, t.Name)
+			printMessages(l, msgs)
+		}
+	}
+
+	s := run.summary()
+	if s.flaky > 0 {
+		l.Warnf("%d flaky test(s) passed after re-run:", s.flaky)
+		for _, p := range run.packages {
+			for _, t := range p.Tests {
+				if run.isFlaky(p.Name, t) {
+					l.Logf("\t- %s.%s", p.Name, t.Name)
+				}
+			}
+		}
+	}
+
+	l.Infof("Tests: %d passed, %d failed, %d skipped, %d flaky", s.passed, s.failed, s.skipped, s.flaky)
+}
+
+func printMessages(l log.Logger, msgs []string) {
+	w := l.ErrorWriter()
+	for _, m := range msgs {
+		_, _ = fmt.Fprintf(w, "\t%s\n", m)
+	}
+}
+
+// readLines reads "go test" JSON output and reports packages progress
+func readLines(l log.Logger, r io.Reader) (report.Lines, error) {
+	lines := make(report.Lines, 0)
+	br := bufio.NewReader(r)
+	for {
+		data, err := br.ReadBytes('\n')
+		if len(bytes.TrimSpace(data)) > 0 {
+			if line, parseErr := report.Parse(data); parseErr != nil || line.Action == "" {
+				// "go test" tool sometimes reports errors not as JSON events
+				_, _ = l.ErrorWriter().Write(data)
+			} else {
+				lines = append(lines, line)
+				reportProgress(l, line)
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return lines, nil
+		}
+
+		if err != nil {
+			return lines, err
+		}
+	}
+}
+
+func reportProgress(l log.Logger, line report.Line) {
+	if line.Action == actionBuildOutput {
+		_, _ = l.ErrorWriter().Write([]byte(line.Output))
+		return
+	}
+
+	if line.Test != "" {
+		return
+	}
+
+	switch line.Action {
+	case "pass":
+		l.Logf("ok   %s (%.2fs)", line.Package, line.Elapsed)
+	case "fail":
+		l.Errorf("FAIL %s (%.2fs)", line.Package, line.Elapsed)
+	case "skip":
+		l.Debugf("test: no tests in %s", line.Package)
+	}
+}
+
+func hasFailures(pkgs []*report.PackageResult) bool {
+	for _, p := range pkgs {
+		if p.Failed() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Cancel cancels action execution
+func (a *Action) Cancel(_ *job.RunContext) error {
+	// "go test" process is stopped by job context
+	return nil
+}
@@ -0,0 +1,80 @@
+package gotest
+
+import (
+	"context"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-gilbert/gilbert/internal/manifest"
+	"github.com/go-gilbert/gilbert/internal/manifest/expr"
+	"github.com/go-gilbert/gilbert/internal/runner/job"
+	"github.com/go-gilbert/gilbert/internal/scope"
+	"github.com/go-gilbert/gilbert/internal/support/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAction_Call(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping 'go test' run in short mode")
+	}
+
+	projectDir, err := filepath.Abs(filepath.Join("testdata", "project"))
+	require.NoError(t, err)
+
+	cases := map[string]struct {
+		retries int
+		err     string
+		flaky   int
+		failed  int
+	}{
+		"detect flaky test": {
+			retries: 1,
+			flaky:   1,
+		},
+		"fail without retries": {
+			err:    "tests failed in 1 package(s)",
+			failed: 1,
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			t.Setenv("FLAKY_MARKER", filepath.Join(t.TempDir(), "marker"))
+			reportFile := filepath.Join(t.TempDir(), "out", "junit.xml")
+			l := &test.Log{T: t}
+			s := scope.CreateScope(expr.SpecV2Parser{}, projectDir, nil)
+			a, err := NewAction(s, manifest.ActionParams{
+				"retries":     c.retries,
+				"junitReport": reportFile,
+			})
+			require.NoError(t, err)
+
+			err = a.Call(job.NewRunContext(context.Background(), nil, l), nil)
+			if c.err != "" {
+				require.EqualError(t, err, c.err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			data, err := os.ReadFile(reportFile)
+			require.NoError(t, err)
+
+			var r junitTestSuites
+			require.NoError(t, xml.Unmarshal(data, &r))
+			require.Len(t, r.Suites, 1)
+			assert.Equal(t, 6, r.Tests)
+			assert.Equal(t, c.failed, r.Failures)
+			assert.Equal(t, 1, r.Skipped)
+
+			flaky := 0
+			for _, tc := range r.Suites[0].TestCases {
+				flaky += len(tc.FlakyFailures)
+			}
+
+			assert.Equal(t, c.flaky, flaky)
+		})
+	}
+}
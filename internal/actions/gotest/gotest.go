@@ -0,0 +1,37 @@
+/*
+Package gotest implements "test" action which runs Go tests, re-runs failed tests and writes JUnit reports
+*/
+package gotest
+
+import (
+	"path/filepath"
+
+	"github.com/go-gilbert/gilbert/internal/manifest"
+	"github.com/go-gilbert/gilbert/internal/runner"
+	"github.com/go-gilbert/gilbert/internal/scope"
+)
+
+// NewAction creates a new test action handler instance
+func NewAction(scope *scope.Scope, params manifest.ActionParams) (runner.ActionHandler, error) {
+	p := newParams()
+	if err := params.Unmarshal(&p); err != nil {
+		return nil, err
+	}
+
+	if err := scope.Scan(&p.Run, &p.Skip, &p.JUnitReport); err != nil {
+		return nil, err
+	}
+
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+
+	if p.JUnitReport != "" && !filepath.IsAbs(p.JUnitReport) {
+		p.JUnitReport = filepath.Join(scope.Environment().ProjectDirectory, p.JUnitReport)
+	}
+
+	return &Action{
+		scope:  scope,
+		params: p,
+	}, nil
+}
@@ -0,0 +1,155 @@
+package gotest
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-gilbert/gilbert/internal/actions/cover/report"
+)
+
+const (
+	failureMessage = "Failed"
+	errorMessage   = "Package tests failed"
+	skippedMessage = "Skipped"
+
+	// errorCaseName is test case name for package errors not related to tests (e.g. build errors)
+	errorCaseName = "[Test Execution Error]"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string `xml:"classname,attr"`
+	Name      string `xml:"name,attr"`
+	Time      string `xml:"time,attr"`
+
+	Failure *junitFailure `xml:"failure,omitempty"`
+	Error   *junitFailure `xml:"error,omitempty"`
+	Skipped *junitSkipped `xml:"skipped,omitempty"`
+
+	// FlakyFailures contains failures of tests passed after re-run (Surefire extension)
+	FlakyFailures []junitFailure `xml:"flakyFailure,omitempty"`
+}
+
+type junitFailure struct {
+	Message  string `xml:"message,attr"`
+	Contents string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// newJUnitReport creates JUnit report from test results.
+//
+// Packages without tests are not included in report.
+func newJUnitReport(run *testRun) junitTestSuites {
+	r := junitTestSuites{Suites: make([]junitTestSuite, 0, len(run.packages))}
+	var elapsed float64
+	for _, p := range run.packages {
+		if p.Skipped() {
+			continue
+		}
+
+		suite := newJUnitTestSuite(run, p)
+		r.Tests += suite.Tests
+		r.Failures += suite.Failures
+		r.Errors += suite.Errors
+		r.Skipped += suite.Skipped
+		elapsed += p.Elapsed
+		r.Suites = append(r.Suites, suite)
+	}
+
+	r.Time = formatSeconds(elapsed)
+	return r
+}
+
+func newJUnitTestSuite(run *testRun, p *report.PackageResult) junitTestSuite {
+	suite := junitTestSuite{
+		Name:      p.Name,
+		Time:      formatSeconds(p.Elapsed),
+		TestCases: make([]junitTestCase, 0, len(p.Tests)),
+	}
+
+	for _, t := range p.Tests {
+		tc := junitTestCase{
+			ClassName: p.Name,
+			Name:      t.Name,
+			Time:      formatSeconds(t.Elapsed),
+		}
+
+		switch {
+		case t.Skipped():
+			tc.Skipped = &junitSkipped{Message: skippedMessage}
+			suite.Skipped++
+		case t.Failed() && run.isFlaky(p.Name, t):
+			tc.FlakyFailures = []junitFailure{{Message: failureMessage, Contents: joinOutput(t.Output)}}
+		case t.Failed():
+			tc.Failure = &junitFailure{Message: failureMessage, Contents: joinOutput(t.Output)}
+			suite.Failures++
+		}
+
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+
+	if isPackageError(p) {
+		suite.TestCases = append(suite.TestCases, junitTestCase{
+			ClassName: p.Name,
+			Name:      errorCaseName,
+			Time:      formatSeconds(0),
+			Error:     &junitFailure{Message: errorMessage, Contents: joinOutput(p.Output)},
+		})
+		suite.Errors++
+	}
+
+	suite.Tests = len(suite.TestCases)
+	return suite
+}
+
+// writeJUnitReport saves test results to a JUnit XML file
+func writeJUnitReport(fileName string, run *testRun) error {
+	if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+		return fmt.Errorf("failed to create JUnit report directory, %s", err)
+	}
+
+	data, err := xml.MarshalIndent(newJUnitReport(run), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to create JUnit report, %s", err)
+	}
+
+	data = append([]byte(xml.Header), data...)
+	if err := os.WriteFile(fileName, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write JUnit report, %s", err)
+	}
+
+	return nil
+}
+
+func formatSeconds(sec float64) string {
+	return fmt.Sprintf("%.3f", sec)
+}
+
+func joinOutput(output []string) string {
+	return strings.Join(output, "")
+}
@@ -0,0 +1,117 @@
+package gotest
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	shuffleOn  = "on"
+	shuffleOff = "off"
+)
+
+type params struct {
+	// Packages is list of packages to test
+	Packages []string `mapstructure:"packages"`
+
+	// Run is a regular expression to select tests to run ("-run" flag)
+	Run string `mapstructure:"run"`
+
+	// Skip is a regular expression to select tests to skip ("-skip" flag)
+	Skip string `mapstructure:"skip"`
+
+	// Race enables data race detection
+	Race bool `mapstructure:"race"`
+
+	// Count is number of times to run each test
+	Count int `mapstructure:"count"`
+
+	// Shuffle randomizes tests execution order. Accepts "on", "off" or a seed number.
+	Shuffle string `mapstructure:"shuffle"`
+
+	// Timeout is tests execution timeout
+	Timeout string `mapstructure:"timeout"`
+
+	// Retries is max number of failed tests re-runs
+	Retries int `mapstructure:"retries"`
+
+	// JUnitReport is JUnit XML report file path
+	JUnitReport string `mapstructure:"junitReport"`
+}
+
+func (p *params) validate() error {
+	if p.Count < 0 {
+		return fmt.Errorf("tests count should be positive (got %d)", p.Count)
+	}
+
+	if p.Retries < 0 {
+		return fmt.Errorf("retries count should be positive (got %d)", p.Retries)
+	}
+
+	if p.Timeout != "" {
+		if _, err := time.ParseDuration(p.Timeout); err != nil {
+			return fmt.Errorf("invalid timeout value '%s'", p.Timeout)
+		}
+	}
+
+	switch p.Shuffle {
+	case "", shuffleOn, shuffleOff:
+	default:
+		if _, err := strconv.ParseInt(p.Shuffle, 10, 64); err != nil {
+			return fmt.Errorf("invalid shuffle value '%s' (expected %s, %s or seed number)", p.Shuffle, shuffleOn, shuffleOff)
+		}
+	}
+
+	for _, expr := range []string{p.Run, p.Skip} {
+		if _, err := regexp.Compile(expr); err != nil {
+			return fmt.Errorf("invalid test name pattern '%s': %s", expr, err)
+		}
+	}
+
+	return nil
+}
+
+// testFlags returns "go test" flags common for all test runs
+func (p *params) testFlags() []string {
+	args := []string{"-json"}
+	if p.Race {
+		args = append(args, "-race")
+	}
+
+	if p.Count > 0 {
+		args = append(args, "-count="+strconv.Itoa(p.Count))
+	}
+
+	if p.Shuffle != "" {
+		args = append(args, "-shuffle="+p.Shuffle)
+	}
+
+	if p.Timeout != "" {
+		args = append(args, "-timeout="+p.Timeout)
+	}
+
+	if p.Skip != "" {
+		args = append(args, "-skip="+p.Skip)
+	}
+
+	return args
+}
+
+// runPattern returns "-run" flag value which matches only specified top-level tests
+func runPattern(tests []string) string {
+	names := make([]string, 0, len(tests))
+	for _, t := range tests {
+		names = append(names, regexp.QuoteMeta(t))
+	}
+
+	return "^(" + strings.Join(names, "|") + ")$"
+}
+
+func newParams() params {
+	return params{
+		Packages: []string{"./..."},
+	}
+}
@@ -0,0 +1,66 @@
+package gotest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParamsValidate(t *testing.T) {
+	cases := map[string]struct {
+		p   params
+		err string
+	}{
+		"accept valid params": {
+			p: params{Count: 1, Retries: 2, Shuffle: "on", Timeout: "5m", Run: "^TestFoo$"},
+		},
+		"accept shuffle seed": {
+			p: params{Shuffle: "1234"},
+		},
+		"validate count": {
+			p:   params{Count: -1},
+			err: "tests count should be positive",
+		},
+		"validate retries": {
+			p:   params{Retries: -1},
+			err: "retries count should be positive",
+		},
+		"validate timeout": {
+			p:   params{Timeout: "forever"},
+			err: "invalid timeout value 'forever'",
+		},
+		"validate shuffle": {
+			p:   params{Shuffle: "yes"},
+			err: "invalid shuffle value 'yes'",
+		},
+		"validate run pattern": {
+			p:   params{Run: "Test("},
+			err: "invalid test name pattern 'Test('",
+		},
+	}
+
+	for n, c := range cases {
+		t.Run("should "+n, func(t *testing.T) {
+			err := c.p.validate()
+			if c.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), c.err)
+		})
+	}
+}
+
+func TestParamsTestFlags(t *testing.T) {
+	p := params{Race: true, Count: 2, Shuffle: "off", Timeout: "1m", Skip: "TestSlow"}
+	assert.Equal(t, []string{"-json", "-race", "-count=2", "-shuffle=off", "-timeout=1m", "-skip=TestSlow"}, p.testFlags())
+	defaults := newParams()
+	assert.Equal(t, []string{"-json"}, defaults.testFlags())
+}
+
+func TestRunPattern(t *testing.T) {
+	assert.Equal(t, `^(TestFoo|TestBar\.Baz)$`, runPattern([]string{"TestFoo", "TestBar.Baz"}))
+}
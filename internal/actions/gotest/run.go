@@ -0,0 +1,149 @@
+package gotest
+
+import "github.com/go-gilbert/gilbert/internal/actions/cover/report"
+
+type testKey struct {
+	pkg  string
+	test string
+}
+
+// retryGroup is a set of failed tests from a single package
+type retryGroup struct {
+	pkg   string
+	tests []string
+}
+
+// testRun contains results of tests run and its retries
+type testRun struct {
+	packages []*report.PackageResult
+
+	// flaky contains failed tests which passed after re-run
+	flaky map[testKey]struct{}
+}
+
+func newTestRun(pkgs []*report.PackageResult) *testRun {
+	return &testRun{
+		packages: pkgs,
+		flaky:    make(map[testKey]struct{}),
+	}
+}
+
+// isFlaky checks if test failed, but passed after re-run
+func (r *testRun) isFlaky(pkg string, t *report.TestResult) bool {
+	_, ok := r.flaky[testKey{pkg: pkg, test: t.Name}]
+	return ok
+}
+
+// failedTests returns failed tests which weren't fixed by re-run
+func (r *testRun) failedTests(p *report.PackageResult) []*report.TestResult {
+	failed := make([]*report.TestResult, 0)
+	for _, t := range p.Tests {
+		if t.Failed() && !r.isFlaky(p.Name, t) {
+			failed = append(failed, t)
+		}
+	}
+
+	return failed
+}
+
+// isPackageError checks if package failed not because of failed tests (e.g. build error)
+func isPackageError(p *report.PackageResult) bool {
+	return p.Failed() && len(p.FailedTests()) == 0
+}
+
+// retryGroups returns failed top-level tests which should be re-run.
+//
+// Packages which failed not because of tests (e.g. because of build error) are not re-run.
+func (r *testRun) retryGroups() []retryGroup {
+	groups := make([]retryGroup, 0)
+	for _, p := range r.packages {
+		if !p.Failed() {
+			continue
+		}
+
+		tests := make([]string, 0)
+		for _, name := range p.FailedTests() {
+			if _, ok := r.flaky[testKey{pkg: p.Name, test: name}]; !ok {
+				tests = append(tests, name)
+			}
+		}
+
+		if len(tests) > 0 {
+			groups = append(groups, retryGroup{pkg: p.Name, tests: tests})
+		}
+	}
+
+	return groups
+}
+
+// merge applies results of failed tests re-run.
+//
+// Failed tests which passed after re-run are marked as flaky.
+func (r *testRun) merge(retried []*report.PackageResult) {
+	for _, rp := range retried {
+		p := r.findPackage(rp.Name)
+		if p == nil {
+			continue
+		}
+
+		for _, rt := range rp.Tests {
+			if rt.Failed() || rt.Skipped() {
+				continue
+			}
+
+			if t := p.FindTest(rt.Name); t != nil && t.Failed() {
+				r.flaky[testKey{pkg: p.Name, test: t.Name}] = struct{}{}
+			}
+		}
+	}
+}
+
+func (r *testRun) findPackage(name string) *report.PackageResult {
+	for _, p := range r.packages {
+		if p.Name == name {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// failedPackages returns count of packages with failed tests or errors
+func (r *testRun) failedPackages() int {
+	count := 0
+	for _, p := range r.packages {
+		if isPackageError(p) || len(r.failedTests(p)) > 0 {
+			count++
+		}
+	}
+
+	return count
+}
+
+// summary contains count of tests by result status
+type summary struct {
+	passed  int
+	failed  int
+	skipped int
+	flaky   int
+}
+
+func (r *testRun) summary() summary {
+	s := summary{}
+	for _, p := range r.packages {
+		for _, t := range p.Tests {
+			switch {
+			case t.Skipped():
+				s.skipped++
+			case !t.Failed():
+				s.passed++
+			case r.isFlaky(p.Name, t):
+				s.flaky++
+			default:
+				s.failed++
+			}
+		}
+	}
+
+	return s
+}
@@ -0,0 +1,63 @@
+package gotest
+
+import (
+	"testing"
+
+	"github.com/go-gilbert/gilbert/internal/actions/cover/report"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPkg = "example.com/foo"
+
+func newTestResults(lines ...report.Line) []*report.PackageResult {
+	return report.Lines(lines).Results()
+}
+
+func TestTestRun_Merge(t *testing.T) {
+	run := newTestRun(newTestResults(
+		report.Line{Action: "run", Package: testPkg, Test: "TestFlaky"},
+		report.Line{Action: "run", Package: testPkg, Test: "TestFlaky/sub"},
+		report.Line{Action: "output", Package: testPkg, Test: "TestFlaky/sub", Output: "    foo_test.go:10: boom\n"},
+		report.Line{Action: "fail", Package: testPkg, Test: "TestFlaky/sub"},
+		report.Line{Action: "fail", Package: testPkg, Test: "TestFlaky"},
+		report.Line{Action: "run", Package: testPkg, Test: "TestBroken"},
+		report.Line{Action: "fail", Package: testPkg, Test: "TestBroken"},
+		report.Line{Action: "run", Package: testPkg, Test: "TestPass"},
+		report.Line{Action: "pass", Package: testPkg, Test: "TestPass"},
+		report.Line{Action: "fail", Package: testPkg},
+		report.Line{Action: "output", Package: "example.com/bar", Output: "# build failed\n"},
+		report.Line{Action: "fail", Package: "example.com/bar"},
+	))
+
+	assert.Equal(t, []retryGroup{{pkg: testPkg, tests: []string{"TestFlaky", "TestBroken"}}}, run.retryGroups())
+	assert.Equal(t, summary{passed: 1, failed: 3}, run.summary())
+
+	run.merge(newTestResults(
+		report.Line{Action: "run", Package: testPkg, Test: "TestFlaky"},
+		report.Line{Action: "run", Package: testPkg, Test: "TestFlaky/sub"},
+		report.Line{Action: "pass", Package: testPkg, Test: "TestFlaky/sub"},
+		report.Line{Action: "pass", Package: testPkg, Test: "TestFlaky"},
+		report.Line{Action: "run", Package: testPkg, Test: "TestBroken"},
+		report.Line{Action: "fail", Package: testPkg, Test: "TestBroken"},
+		report.Line{Action: "fail", Package: testPkg},
+	))
+
+	assert.Equal(t, []retryGroup{{pkg: testPkg, tests: []string{"TestBroken"}}}, run.retryGroups())
+	assert.Equal(t, summary{passed: 1, failed: 1, flaky: 2}, run.summary())
+	assert.Equal(t, 2, run.failedPackages())
+
+	r := newJUnitReport(run)
+	assert.Equal(t, 5, r.Tests)
+	assert.Equal(t, 1, r.Failures)
+	assert.Equal(t, 1, r.Errors)
+	if assert.Len(t, r.Suites, 2) {
+		flaky := r.Suites[0].TestCases[1]
+		assert.Equal(t, "TestFlaky/sub", flaky.Name)
+		assert.Nil(t, flaky.Failure)
+		assert.Equal(t, []junitFailure{{Message: failureMessage, Contents: "    foo_test.go:10: boom\n"}}, flaky.FlakyFailures)
+
+		pkgErr := r.Suites[1].TestCases[0]
+		assert.Equal(t, errorCaseName, pkgErr.Name)
+		assert.Equal(t, &junitFailure{Message: errorMessage, Contents: "# build failed\n"}, pkgErr.Error)
+	}
+}
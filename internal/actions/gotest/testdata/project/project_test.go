@@ -0,0 +1,35 @@
+package project
+
+import (
+	"os"
+	"testing"
+)
+
+func TestPass(t *testing.T) {}
+
+func TestSkip(t *testing.T) {
+	t.Skip("not implemented")
+}
+
+func TestTable(t *testing.T) {
+	t.Run("first", func(t *testing.T) {})
+	t.Run("second", func(t *testing.T) {})
+}
+
+// TestFlaky fails only on first run
+func TestFlaky(t *testing.T) {
+	marker := os.Getenv("FLAKY_MARKER")
+	if marker == "" {
+		t.Skip("FLAKY_MARKER is not set")
+	}
+
+	if _, err := os.Stat(marker); err == nil {
+		return
+	}
+
+	if err := os.WriteFile(marker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Fatal("flaky failure")
+}
@@ -64,7 +64,9 @@ func (a *Action) Call(ctx *job.RunContext, r *runner.TaskRunner) error {
 				if childCtx.IsAlive() {
 					childCtx.Cancel()
 				}
-				childCtx = ctx.ChildContext()
+
+				// shell expressions are evaluated again on each re-run
+				childCtx = ctx.ChildContextWith(r.WithCommandCache(ctx.Context()))
 				go a.invokeJob(childCtx, r)
 			}
 		}
@@ -0,0 +1,310 @@
+// Package cache implements content-addressed storage of job outputs.
+//
+// Each output file is stored as a blob addressed by sha256 digest of its contents,
+// and a list of job output files is stored as an entry addressed by job inputs digest.
+package cache
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-gilbert/gilbert/internal/storage"
+)
+
+// entry is a list of job output files
+type entry struct {
+	Files []fileEntry `json:"files"`
+}
+
+type fileEntry struct {
+	Path   string      `json:"path"`
+	Mode   fs.FileMode `json:"mode"`
+	Digest string      `json:"digest"`
+}
+
+// Cache stores job outputs in a local store and optionally shares them using remote cache
+type Cache struct {
+	store  *Store
+	remote *Remote
+}
+
+// New creates a new cache instance.
+//
+// Remote is optional.
+func New(store *Store, remote *Remote) *Cache {
+	return &Cache{store: store, remote: remote}
+}
+
+// NewDefault creates a cache in Gilbert storage directory.
+//
+// Remote cache is used only if remote URL is not empty.
+func NewDefault(remoteURL string) (*Cache, error) {
+	dir, err := storage.Path(storage.Cache)
+	if err != nil {
+		return nil, err
+	}
+
+	var remote *Remote
+	if remoteURL != "" {
+		remote = NewRemote(remoteURL, nil)
+	}
+
+	return New(NewStore(dir), remote), nil
+}
+
+// Save stores files matched by output globs with specified key.
+//
+// Output paths should be located inside base directory.
+func (c *Cache) Save(ctx context.Context, key, baseDir string, outputs []string) error {
+	var e entry
+	for _, pattern := range outputs {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return err
+		}
+
+		for _, match := range matches {
+			err := filepath.WalkDir(match, func(path string, d fs.DirEntry, err error) error {
+				if err != nil || !d.Type().IsRegular() {
+					return err
+				}
+
+				f, err := c.saveFile(ctx, baseDir, path)
+				if err != nil {
+					return fmt.Errorf("failed to cache %q: %w", path, err)
+				}
+
+				e.Files = append(e.Files, f)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(e.Files) == 0 {
+		// Nothing to cache, job will be started next time
+		return nil
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	if err := c.store.Write(KindEntry, key, bytes.NewReader(data)); err != nil {
+		return err
+	}
+
+	if c.remote == nil {
+		return nil
+	}
+
+	return c.remote.Put(ctx, KindEntry, key, bytes.NewReader(data))
+}
+
+func (c *Cache) saveFile(ctx context.Context, baseDir, path string) (fileEntry, error) {
+	rel, err := filepath.Rel(baseDir, path)
+	if err != nil {
+		return fileEntry{}, err
+	}
+
+	if isOutside(rel) {
+		return fileEntry{}, errors.New("file is outside of project directory")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileEntry{}, err
+	}
+
+	digest, err := fileDigest(path)
+	if err != nil {
+		return fileEntry{}, err
+	}
+
+	if !c.store.Has(KindBlob, digest) {
+		if err := c.writeBlob(digest, path); err != nil {
+			return fileEntry{}, err
+		}
+	}
+
+	if c.remote != nil {
+		f, err := c.store.Open(KindBlob, digest)
+		if err != nil {
+			return fileEntry{}, err
+		}
+
+		defer f.Close()
+		if err := c.remote.Put(ctx, KindBlob, digest, f); err != nil {
+			return fileEntry{}, err
+		}
+	}
+
+	return fileEntry{Path: filepath.ToSlash(rel), Mode: info.Mode().Perm(), Digest: digest}, nil
+}
+
+func (c *Cache) writeBlob(digest, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+	return c.store.Write(KindBlob, digest, f)
+}
+
+// Restore restores files stored with specified key into base directory.
+//
+// Returns false if there are no files in the cache for the key.
+func (c *Cache) Restore(ctx context.Context, key, baseDir string) (bool, error) {
+	ok, err := c.fetch(ctx, KindEntry, key)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	f, err := c.store.Open(KindEntry, key)
+	if err != nil {
+		return false, err
+	}
+
+	var e entry
+	err = json.NewDecoder(f).Decode(&e)
+	_ = f.Close()
+	if err != nil {
+		return false, fmt.Errorf("corrupted cache entry %s: %w", key, err)
+	}
+
+	// Entry might come from untrusted remote cache, so check all paths before any change
+	dests := make([]string, len(e.Files))
+	for i, file := range e.Files {
+		dests[i], err = restorePath(baseDir, file.Path)
+		if err != nil {
+			return false, fmt.Errorf("invalid cache entry %s: %w", key, err)
+		}
+	}
+
+	// Fetch all files before restoring them to not leave outputs in inconsistent state
+	for _, file := range e.Files {
+		ok, err := c.fetch(ctx, KindBlob, file.Digest)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+
+	for i, file := range e.Files {
+		if err := c.restoreFile(dests[i], file); err != nil {
+			return false, fmt.Errorf("failed to restore %q: %w", file.Path, err)
+		}
+	}
+
+	return true, nil
+}
+
+// restorePath returns destination path of restored file.
+//
+// Returns an error if path is absolute or points outside of base directory.
+func restorePath(baseDir, path string) (string, error) {
+	if path == "" || filepath.IsAbs(filepath.FromSlash(path)) || strings.HasPrefix(path, "/") {
+		return "", fmt.Errorf("file path %q is not relative", path)
+	}
+
+	dest := filepath.Join(baseDir, filepath.FromSlash(path))
+	rel, err := filepath.Rel(baseDir, dest)
+	if err != nil {
+		return "", err
+	}
+
+	if rel == "." || isOutside(rel) {
+		return "", fmt.Errorf("file path %q is outside of project directory", path)
+	}
+
+	return dest, nil
+}
+
+// isOutside checks if relative path points outside of base directory
+func isOutside(rel string) bool {
+	return rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func (c *Cache) restoreFile(dest string, file fileEntry) error {
+	src, err := c.store.Open(KindBlob, file.Digest)
+	if err != nil {
+		return err
+	}
+
+	defer src.Close()
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, file.Mode)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, src); err != nil {
+		_ = out.Close()
+		return err
+	}
+
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	// OpenFile doesn't change mode of existing file
+	return os.Chmod(dest, file.Mode)
+}
+
+// fetch downloads object from remote cache if it's missing in local store
+func (c *Cache) fetch(ctx context.Context, kind Kind, digest string) (bool, error) {
+	if c.store.Has(kind, digest) {
+		return true, nil
+	}
+
+	if c.remote == nil {
+		return false, nil
+	}
+
+	body, err := c.remote.Get(ctx, kind, digest)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	defer body.Close()
+	if err := c.store.Write(kind, digest, body); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func fileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
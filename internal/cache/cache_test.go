@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-gilbert/gilbert/internal/support/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testKey = "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+
+func TestCache_Remote(t *testing.T) {
+	srv := httptest.NewServer(NewServer(NewStore(t.TempDir()), &test.Log{T: t}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	src := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "build", "bin"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "build", "bin", "app"), []byte("app"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "build", "lib.a"), []byte("lib"), 0644))
+
+	c1 := New(NewStore(t.TempDir()), NewRemote(srv.URL, srv.Client()))
+	require.NoError(t, c1.Save(ctx, testKey, src, []string{filepath.Join(src, "build")}))
+
+	// cache with empty local store should download files from remote
+	dest := t.TempDir()
+	c2 := New(NewStore(t.TempDir()), NewRemote(srv.URL+"/", srv.Client()))
+	ok, err := c2.Restore(ctx, testKey, dest)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	data, err := os.ReadFile(filepath.Join(dest, "build", "bin", "app"))
+	require.NoError(t, err)
+	assert.Equal(t, "app", string(data))
+	data, err = os.ReadFile(filepath.Join(dest, "build", "lib.a"))
+	require.NoError(t, err)
+	assert.Equal(t, "lib", string(data))
+
+	ok, err = c2.Restore(ctx, strings.Repeat("0", len(testKey)), dest)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestCache_Save(t *testing.T) {
+	ctx := context.Background()
+	src := t.TempDir()
+	c := New(NewStore(t.TempDir()), nil)
+
+	// nothing to save
+	require.NoError(t, c.Save(ctx, testKey, src, []string{filepath.Join(src, "*.txt")}))
+	ok, err := c.Restore(ctx, testKey, src)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	outside := filepath.Join(t.TempDir(), "foo.txt")
+	require.NoError(t, os.WriteFile(outside, []byte("foo"), 0644))
+	err = c.Save(ctx, testKey, src, []string{outside})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "outside of project directory")
+}
+
+func TestCache_Restore_InvalidPath(t *testing.T) {
+	cases := map[string]string{
+		"parent directory": "../../.bashrc",
+		"nested parent":    "build/../../foo",
+		"absolute path":    "/etc/passwd",
+		"base directory":   ".",
+	}
+
+	for n, path := range cases {
+		t.Run(n, func(t *testing.T) {
+			ctx := context.Background()
+			root := t.TempDir()
+			baseDir := filepath.Join(root, "project", "src")
+			require.NoError(t, os.MkdirAll(baseDir, 0755))
+
+			store := NewStore(t.TempDir())
+			require.NoError(t, store.Write(KindBlob, testKey, strings.NewReader("foo")))
+			data, err := json.Marshal(entry{Files: []fileEntry{
+				{Path: path, Mode: 0644, Digest: testKey},
+			}})
+			require.NoError(t, err)
+			require.NoError(t, store.Write(KindEntry, testKey, bytes.NewReader(data)))
+
+			ok, err := New(store, nil).Restore(ctx, testKey, baseDir)
+			require.Error(t, err)
+			require.False(t, ok)
+			require.Contains(t, err.Error(), "invalid cache entry")
+
+			_, err = os.Stat(filepath.Join(root, ".bashrc"))
+			require.True(t, os.IsNotExist(err))
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Remote is a remote HTTP cache.
+//
+// Objects are read and written with GET and PUT requests to "<url>/<kind>/<digest>",
+// so any static file server with upload support can be used as a remote.
+type Remote struct {
+	url    string
+	client *http.Client
+}
+
+// NewRemote creates a new remote cache client
+func NewRemote(url string, client *http.Client) *Remote {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &Remote{url: strings.TrimSuffix(url, "/"), client: client}
+}
+
+func (r *Remote) objectURL(kind Kind, digest string) string {
+	return r.url + "/" + string(kind) + "/" + digest
+}
+
+// Get opens an object for reading.
+//
+// Returns os.ErrNotExist error if object not exists.
+func (r *Remote) Get(ctx context.Context, kind Kind, digest string) (io.ReadCloser, error) {
+	uri := r.objectURL(kind, digest)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch rsp.StatusCode {
+	case http.StatusOK:
+		return rsp.Body, nil
+	case http.StatusNotFound:
+		_ = rsp.Body.Close()
+		return nil, os.ErrNotExist
+	default:
+		_ = rsp.Body.Close()
+		return nil, fmt.Errorf("failed to get %q: %s", uri, rsp.Status)
+	}
+}
+
+// Put uploads an object
+func (r *Remote) Put(ctx context.Context, kind Kind, digest string, body io.Reader) error {
+	uri := r.objectURL(kind, digest)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, uri, body)
+	if err != nil {
+		return err
+	}
+
+	rsp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer rsp.Body.Close()
+	_, _ = io.Copy(io.Discard, rsp.Body)
+	if rsp.StatusCode < 200 || rsp.StatusCode > 299 {
+		return fmt.Errorf("failed to upload %q: %s", uri, rsp.Status)
+	}
+
+	return nil
+}
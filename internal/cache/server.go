@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/go-gilbert/gilbert/internal/log"
+)
+
+// Server is an HTTP remote cache backend which stores objects in a local store
+type Server struct {
+	store *Store
+	log   log.Logger
+}
+
+// NewServer creates a new remote cache server
+func NewServer(store *Store, l log.Logger) *Server {
+	return &Server{store: store, log: l}
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	kind, digest, ok := parseObjectPath(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		s.serveObject(w, r, kind, digest)
+	case http.MethodPut:
+		s.putObject(w, r, kind, digest)
+	default:
+		w.Header().Set("Allow", "GET, HEAD, PUT")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) serveObject(w http.ResponseWriter, r *http.Request, kind Kind, digest string) {
+	f, err := s.store.Open(kind, digest)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			http.NotFound(w, r)
+			return
+		}
+
+		s.log.Errorf("cache: failed to read %s/%s: %s", kind, digest, err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	defer f.Close()
+	w.Header().Set("Content-Type", "application/octet-stream")
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	if _, err := io.Copy(w, f); err != nil {
+		s.log.Debugf("cache: failed to send %s/%s: %s", kind, digest, err)
+	}
+}
+
+func (s *Server) putObject(w http.ResponseWriter, r *http.Request, kind Kind, digest string) {
+	if err := s.store.Write(kind, digest, r.Body); err != nil {
+		s.log.Errorf("cache: failed to save %s/%s: %s", kind, digest, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.log.Debugf("cache: saved %s/%s", kind, digest)
+	w.WriteHeader(http.StatusCreated)
+}
+
+// parseObjectPath extracts object kind and digest from "/<kind>/<digest>" path
+func parseObjectPath(p string) (kind Kind, digest string, ok bool) {
+	chunks := strings.Split(strings.Trim(p, "/"), "/")
+	if len(chunks) != 2 {
+		return "", "", false
+	}
+
+	kind, digest = Kind(chunks[0]), chunks[1]
+	if kind != KindBlob && kind != KindEntry {
+		return "", "", false
+	}
+
+	return kind, digest, ValidDigest(digest)
+}
@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-gilbert/gilbert/internal/support/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_ServeHTTP(t *testing.T) {
+	// sha256 digest of "foo"
+	const fooDigest = "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+
+	srv := NewServer(NewStore(t.TempDir()), &test.Log{T: t})
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{name: "object not found", method: http.MethodGet, path: "/cas/" + fooDigest, want: http.StatusNotFound},
+		{name: "invalid digest", method: http.MethodPut, path: "/cas/../foo", body: "foo", want: http.StatusNotFound},
+		{name: "invalid kind", method: http.MethodPut, path: "/foo/" + fooDigest, body: "foo", want: http.StatusNotFound},
+		{name: "digest mismatch", method: http.MethodPut, path: "/cas/" + fooDigest, body: "bar", want: http.StatusBadRequest},
+		{name: "upload object", method: http.MethodPut, path: "/cas/" + fooDigest, body: "foo", want: http.StatusCreated},
+		{name: "object exists", method: http.MethodHead, path: "/cas/" + fooDigest, want: http.StatusOK},
+		{name: "download object", method: http.MethodGet, path: "/cas/" + fooDigest, want: http.StatusOK, body: "foo"},
+		{name: "unsupported method", method: http.MethodDelete, path: "/cas/" + fooDigest, want: http.StatusMethodNotAllowed},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var req *http.Request
+			if c.method == http.MethodPut {
+				req = httptest.NewRequest(c.method, c.path, strings.NewReader(c.body))
+			} else {
+				req = httptest.NewRequest(c.method, c.path, nil)
+			}
+
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, req)
+			assert.Equal(t, c.want, rec.Code)
+			if c.method == http.MethodGet && c.want == http.StatusOK {
+				assert.Equal(t, c.body, rec.Body.String())
+			}
+		})
+	}
+}
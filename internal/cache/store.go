@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Kind is cache object kind
+type Kind string
+
+const (
+	// KindBlob is a content-addressed file, object key is sha256 digest of its contents
+	KindBlob Kind = "cas"
+
+	// KindEntry is a list of job output files, object key is job inputs digest
+	KindEntry Kind = "ac"
+)
+
+// ErrInvalidDigest means that object key is not a hex-encoded sha256 digest
+var ErrInvalidDigest = errors.New("invalid object digest")
+
+// ValidDigest checks if string is a hex-encoded sha256 digest
+func ValidDigest(digest string) bool {
+	if len(digest) != sha256.Size*2 {
+		return false
+	}
+
+	_, err := hex.DecodeString(digest)
+	return err == nil
+}
+
+// Store is a local directory with cache objects.
+//
+// Objects are stored as "<dir>/<kind>/<digest>".
+type Store struct {
+	dir string
+}
+
+// NewStore creates a new local store in specified directory
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Path returns object file path
+func (s *Store) Path(kind Kind, digest string) (string, error) {
+	if !ValidDigest(digest) {
+		return "", ErrInvalidDigest
+	}
+
+	return filepath.Join(s.dir, string(kind), digest), nil
+}
+
+// Open opens object for reading.
+//
+// Returns os.ErrNotExist error if object not exists.
+func (s *Store) Open(kind Kind, digest string) (*os.File, error) {
+	p, err := s.Path(kind, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(p)
+}
+
+// Has checks if object exists
+func (s *Store) Has(kind Kind, digest string) bool {
+	p, err := s.Path(kind, digest)
+	if err != nil {
+		return false
+	}
+
+	_, err = os.Stat(p)
+	return err == nil
+}
+
+// Write saves an object.
+//
+// Contents of a blob are verified against the digest.
+func (s *Store) Write(kind Kind, digest string, r io.Reader) (err error) {
+	p, err := s.Path(kind, digest)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first, to not expose incomplete object
+	tmp, err := os.CreateTemp(filepath.Dir(p), digest+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tmp.Close()
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	h := sha256.New()
+	if _, err = io.Copy(io.MultiWriter(tmp, h), r); err != nil {
+		return err
+	}
+
+	if kind == KindBlob {
+		if sum := hex.EncodeToString(h.Sum(nil)); sum != digest {
+			return fmt.Errorf("object digest mismatch, want %s but got %s", digest, sum)
+		}
+	}
+
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), p)
+}
@@ -0,0 +1,129 @@
+package coverage
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/go-gilbert/gilbert/internal/actions/cover/history"
+	"github.com/go-gilbert/gilbert/internal/log"
+	"github.com/urfave/cli"
+)
+
+const (
+	flagMaxDrop = "max-drop"
+	flagLimit   = "limit"
+
+	timeFormat = "2006-01-02 15:04"
+)
+
+var (
+	// MaxDropFlag is max allowed coverage drop since previous commit flag
+	MaxDropFlag = cli.Float64Flag{
+		Name:  flagMaxDrop,
+		Usage: "fail if coverage dropped by more than specified percents since previous recorded commit (negative - disabled)",
+		Value: -1,
+	}
+
+	// LimitFlag is count of displayed history entries flag
+	LimitFlag = cli.IntFlag{
+		Name:  flagLimit,
+		Usage: "count of recent commits to display",
+		Value: 10,
+	}
+)
+
+// HistoryAction handles coverage history command
+func HistoryAction(c *cli.Context) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("cannot get current working directory, %v", err)
+	}
+
+	fileName, err := history.Path(cwd)
+	if err != nil {
+		return err
+	}
+
+	entries, err := history.Load(fileName)
+	if err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		log.Default.Log("No coverage history recorded")
+		return nil
+	}
+
+	log.Default.Info("Coverage history:")
+	if err := printTrend(os.Stdout, entries, c.Int(flagLimit)); err != nil {
+		return err
+	}
+
+	cur := entries[len(entries)-1]
+	prev, ok := history.Previous(entries, cur.Commit)
+	if !ok {
+		return nil
+	}
+
+	log.Default.Infof("Package changes since commit %s:", prev.ShortCommit())
+	if err := printChanges(os.Stdout, history.Compare(prev, cur)); err != nil {
+		return err
+	}
+
+	maxDrop := c.Float64(flagMaxDrop)
+	if maxDrop < 0 {
+		return nil
+	}
+
+	return history.CheckDrop(prev, cur, maxDrop)
+}
+
+// printTrend prints total coverage of recent entries
+func printTrend(out io.Writer, entries []history.Entry, limit int) error {
+	start := 0
+	if limit > 0 && len(entries) > limit {
+		start = len(entries) - limit
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COMMIT\tDATE\tTOTAL\tCHANGE")
+	for i := start; i < len(entries); i++ {
+		e := entries[i]
+		change := "-"
+		if i > 0 {
+			change = formatDelta(e.Total - entries[i-1].Total)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%.2f%%\t%s\n", e.ShortCommit(), e.Time.Local().Format(timeFormat), e.Total, change)
+	}
+
+	return w.Flush()
+}
+
+// printChanges prints per package coverage changes
+func printChanges(out io.Writer, changes []history.Change) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PACKAGE\tPREVIOUS\tCURRENT\tCHANGE")
+	for _, c := range changes {
+		switch {
+		case c.Added:
+			fmt.Fprintf(w, "%s\t-\t%.2f%%\tnew\n", c.Package, c.Current)
+		case c.Removed:
+			fmt.Fprintf(w, "%s\t%.2f%%\t-\tremoved\n", c.Package, c.Previous)
+		default:
+			fmt.Fprintf(w, "%s\t%.2f%%\t%.2f%%\t%s\n", c.Package, c.Previous, c.Current, formatDelta(c.Delta()))
+		}
+	}
+
+	return w.Flush()
+}
+
+func formatDelta(delta float64) string {
+	if delta >= 0 {
+		return fmt.Sprintf("+%.2f%%", delta)
+	}
+
+	return fmt.Sprintf("%.2f%%", delta)
+}
@@ -0,0 +1,39 @@
+package coverage
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/go-gilbert/gilbert/internal/actions/cover/history"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintTrend(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 0, 0, time.Local)
+	entries := []history.Entry{
+		{Commit: "1111111111", Time: ts, Total: 40},
+		{Commit: "2222222222", Time: ts, Total: 50},
+		{Commit: "3333333333", Time: ts, Total: 45.5},
+	}
+
+	out := &bytes.Buffer{}
+	require.NoError(t, printTrend(out, entries, 2))
+	assert.Equal(t, "COMMIT   DATE              TOTAL   CHANGE\n"+
+		"2222222  2024-01-02 03:04  50.00%  +10.00%\n"+
+		"3333333  2024-01-02 03:04  45.50%  -4.50%\n", out.String())
+}
+
+func TestPrintChanges(t *testing.T) {
+	out := &bytes.Buffer{}
+	require.NoError(t, printChanges(out, []history.Change{
+		{Package: "bar", Previous: 80, Current: 70},
+		{Package: "baz", Previous: 40, Removed: true},
+		{Package: "qux", Current: 10, Added: true},
+	}))
+	assert.Equal(t, "PACKAGE  PREVIOUS  CURRENT  CHANGE\n"+
+		"bar      80.00%    70.00%   -10.00%\n"+
+		"baz      40.00%    -        removed\n"+
+		"qux      -         10.00%   new\n", out.String())
+}
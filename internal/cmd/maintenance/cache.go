@@ -9,6 +9,7 @@ import (
 const (
 	targetAll     = "all"
 	targetPlugins = "plugins"
+	targetCache   = "cache"
 )
 
 var (
@@ -23,6 +24,12 @@ var (
 		Name:  targetPlugins,
 		Usage: "clear downloaded plugins",
 	}
+
+	// ClearCacheFlag is flag for clearing job outputs cache
+	ClearCacheFlag = cli.BoolFlag{
+		Name:  targetCache,
+		Usage: "clear cached job outputs",
+	}
 )
 
 // ClearCacheAction handles cache clear command
@@ -49,5 +56,12 @@ func ClearCacheAction(ctx *cli.Context) (err error) {
 			return err
 		}
 	}
+
+	if ctx.Bool(targetCache) {
+		log.Default.Log("Clearing job outputs cache...")
+		if err = storage.Delete(storage.Cache); err != nil {
+			return err
+		}
+	}
 	return nil
 }
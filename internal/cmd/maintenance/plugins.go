@@ -0,0 +1,275 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/go-gilbert/gilbert/internal/log"
+	"github.com/go-gilbert/gilbert/internal/manifest"
+	"github.com/go-gilbert/gilbert/internal/plugins"
+	"github.com/go-gilbert/gilbert/internal/plugins/lockfile"
+	"github.com/urfave/cli"
+)
+
+const (
+	flagOlderThan = "older-than"
+
+	timeFormat = "2006-01-02 15:04"
+	day        = 24 * time.Hour
+)
+
+// OlderThanFlag is max age of unused plugins flag for prune command
+var OlderThanFlag = cli.StringFlag{
+	Name:  flagOlderThan,
+	Usage: "remove plugins which were not used for specified duration (e.g. 30d, 12h)",
+	Value: "30d",
+}
+
+// UpdatePluginsAction handles plugins lock file update command.
+//
+// All plugins declared in manifest are fetched again and their lock file entries
+// of current platform are replaced. Entries of other platforms are kept.
+func UpdatePluginsAction(_ *cli.Context) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("cannot get current working directory, %v", err)
+	}
+
+	m, err := manifest.FromDirectory(cwd)
+	if err != nil {
+		return err
+	}
+
+	urls, err := plugins.ExpandURLs(m, cwd)
+	if err != nil {
+		return err
+	}
+
+	lock, err := lockfile.Load(lockfile.Path(filepath.Dir(m.Location())))
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	for _, uri := range urls {
+		log.Default.Logf("Updating plugin '%s'...", uri)
+		if _, err := plugins.Resolve(ctx, uri, lock, plugins.LockUpdate); err != nil {
+			return fmt.Errorf("failed to update plugin '%s':\n%s", uri, err)
+		}
+	}
+
+	lock.Retain(urls)
+
+	if err := lock.Save(); err != nil {
+		return err
+	}
+
+	log.Default.Successf("Lock file %q updated", lockfile.FileName)
+	return nil
+}
+
+// ListPluginsAction handles cached plugins list command
+func ListPluginsAction(_ *cli.Context) error {
+	cached, err := plugins.ListCached()
+	if err != nil {
+		return err
+	}
+
+	if len(cached) == 0 {
+		log.Default.Log("No cached plugins")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tVERSION\tSOURCE\tSIZE\tLAST USED")
+	for _, p := range cached {
+		source := p.URL
+		if source == "" {
+			source = p.Source
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", p.Name, valueOrDash(p.Version), valueOrDash(source),
+			formatSize(p.Size), p.LastUsed.Local().Format(timeFormat))
+	}
+
+	return w.Flush()
+}
+
+// InstallPluginsAction handles plugins install command.
+//
+// Fetches plugins from specified URLs or all plugins declared in manifest if no URL specified.
+// Plugins are verified using project lock file. Plugins declared in manifest
+// which are missing in lock file are added to it.
+func InstallPluginsAction(c *cli.Context) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("cannot get current working directory, %v", err)
+	}
+
+	urls := []string(c.Args())
+	lockDir := cwd
+	if len(urls) == 0 {
+		m, err := manifest.FromDirectory(cwd)
+		if err != nil {
+			return err
+		}
+
+		if urls, err = plugins.ExpandURLs(m, cwd); err != nil {
+			return err
+		}
+
+		lockDir = filepath.Dir(m.Location())
+	}
+
+	if len(urls) == 0 {
+		log.Default.Log("Nothing to install!")
+		return nil
+	}
+
+	lock, err := lockfile.Load(lockfile.Path(lockDir))
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	for _, uri := range urls {
+		a, err := plugins.Resolve(ctx, uri, lock, plugins.LockInstall)
+		if err != nil {
+			return fmt.Errorf("failed to install plugin '%s':\n%s", uri, err)
+		}
+
+		log.Default.Logf("Installed plugin '%s' to %q", uri, a.Path)
+	}
+
+	// Entries of plugins passed as arguments are discarded
+	if c.NArg() == 0 && lock.Changed() {
+		if err := lock.Save(); err != nil {
+			return err
+		}
+	}
+
+	log.Default.Success("Done!")
+	return nil
+}
+
+// RemovePluginAction handles cached plugin remove command
+func RemovePluginAction(c *cli.Context) error {
+	if c.NArg() == 0 {
+		return errors.New("no plugin name or URL specified")
+	}
+
+	cached, err := plugins.ListCached()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range c.Args() {
+		removed := 0
+		for _, p := range cached {
+			if !p.Matches(name) {
+				continue
+			}
+
+			if err := p.Remove(); err != nil {
+				return fmt.Errorf("failed to remove plugin %q: %s", p.Path, err)
+			}
+
+			log.Default.Debugf("plugins: removed %q", p.Path)
+			removed++
+		}
+
+		if removed == 0 {
+			return fmt.Errorf("plugin %q is not cached", name)
+		}
+
+		log.Default.Successf("Removed plugin '%s'", name)
+	}
+
+	return nil
+}
+
+// PrunePluginsAction handles cached plugins prune command
+func PrunePluginsAction(c *cli.Context) error {
+	maxAge, err := parseAge(c.String(flagOlderThan))
+	if err != nil {
+		return fmt.Errorf("invalid --%s value: %s", flagOlderThan, err)
+	}
+
+	cached, err := plugins.ListCached()
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(-maxAge)
+	removed := 0
+	for _, p := range cached {
+		if p.LastUsed.After(deadline) {
+			continue
+		}
+
+		if err := p.Remove(); err != nil {
+			return fmt.Errorf("failed to remove plugin %q: %s", p.Path, err)
+		}
+
+		log.Default.Logf("Removed plugin '%s' (last used %s)", p.Name, p.LastUsed.Local().Format(timeFormat))
+		removed++
+	}
+
+	if removed == 0 {
+		log.Default.Log("Nothing to prune!")
+		return nil
+	}
+
+	log.Default.Successf("Removed %d plugin(s)", removed)
+	return nil
+}
+
+// parseAge parses duration with optional days suffix (e.g. "30d")
+func parseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if days := strings.TrimSuffix(s, "d"); days != s {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("bad days count %q", days)
+		}
+
+		return time.Duration(n) * day, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err == nil && d < 0 {
+		return 0, fmt.Errorf("negative duration %q", s)
+	}
+
+	return d, err
+}
+
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
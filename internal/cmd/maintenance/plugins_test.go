@@ -0,0 +1,48 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAge(t *testing.T) {
+	cases := map[string]struct {
+		expected time.Duration
+		err      string
+	}{
+		"30d":  {expected: 30 * day},
+		"12h":  {expected: 12 * time.Hour},
+		"0d":   {expected: 0},
+		"xd":   {err: `bad days count "x"`},
+		"-1d":  {err: `bad days count "-1"`},
+		"-1h":  {err: `negative duration "-1h"`},
+		"week": {err: `time: invalid duration "week"`},
+	}
+
+	for input, c := range cases {
+		t.Run(input, func(t *testing.T) {
+			got, err := parseAge(input)
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, got)
+		})
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	cases := map[int64]string{
+		12:              "12 B",
+		2048:            "2.0 KiB",
+		5 * 1024 * 1024: "5.0 MiB",
+	}
+
+	for size, expected := range cases {
+		assert.Equal(t, expected, formatSize(size))
+	}
+}
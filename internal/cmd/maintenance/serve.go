@@ -0,0 +1,64 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+
+	"github.com/go-gilbert/gilbert/internal/cache"
+	"github.com/go-gilbert/gilbert/internal/log"
+	"github.com/go-gilbert/gilbert/internal/storage"
+	"github.com/urfave/cli"
+)
+
+const (
+	flagAddr = "addr"
+	flagDir  = "dir"
+)
+
+var (
+	// ServeAddrFlag is remote cache server listen address flag
+	ServeAddrFlag = cli.StringFlag{
+		Name:  flagAddr,
+		Usage: "listen address",
+		Value: ":8080",
+	}
+
+	// ServeDirFlag is remote cache server storage directory flag
+	ServeDirFlag = cli.StringFlag{
+		Name:  flagDir,
+		Usage: "cache directory (default: local cache storage)",
+	}
+)
+
+// ServeCacheAction handles remote cache server command
+func ServeCacheAction(ctx *cli.Context) (err error) {
+	dir := ctx.String(flagDir)
+	if dir == "" {
+		if dir, err = storage.Path(storage.Cache); err != nil {
+			return err
+		}
+	}
+
+	srv := &http.Server{
+		Addr:    ctx.String(flagAddr),
+		Handler: cache.NewServer(cache.NewStore(dir), log.Default),
+	}
+
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, os.Interrupt)
+		<-c
+		log.Default.Log("Shutting down...")
+		_ = srv.Shutdown(context.Background())
+	}()
+
+	log.Default.Logf("Serving cache from %q on %s", dir, srv.Addr)
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
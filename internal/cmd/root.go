@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/fatih/color"
+	"github.com/go-gilbert/gilbert/internal/cmd/coverage"
 	"github.com/go-gilbert/gilbert/internal/cmd/maintenance"
 	"github.com/go-gilbert/gilbert/internal/cmd/scaffold"
 	"github.com/go-gilbert/gilbert/internal/cmd/tasks"
@@ -64,6 +65,15 @@ func NewCmdRoot(ver VersionInfo) *cli.App {
 				cli.StringSliceFlag{
 					Name: tasks.OverrideVarFlag,
 				},
+				cli.IntFlag{
+					Name:  tasks.JobsFlag + ", j",
+					Usage: "max count of actions running at the same time (0 - no limit)",
+				},
+				cli.StringFlag{
+					Name:   tasks.CacheURLFlag,
+					Usage:  "remote cache URL to share job outputs",
+					EnvVar: "GILBERT_CACHE_URL",
+				},
 			},
 		},
 		{
@@ -100,6 +110,105 @@ func NewCmdRoot(ver VersionInfo) *cli.App {
 				verboseFlag,
 				maintenance.ClearAllFlag,
 				maintenance.ClearPluginsFlag,
+				maintenance.ClearCacheFlag,
+			},
+		},
+		{
+			Name:        "cache",
+			Description: "Manage job outputs cache",
+			Usage:       "Manage job outputs cache",
+			Subcommands: []cli.Command{
+				{
+					Name:        "serve",
+					Description: "Starts remote cache HTTP server",
+					Usage:       "Starts remote cache HTTP server",
+					Action:      maintenance.ServeCacheAction,
+					Before:      bootstrap,
+					Flags: []cli.Flag{
+						verboseFlag,
+						maintenance.ServeAddrFlag,
+						maintenance.ServeDirFlag,
+					},
+				},
+			},
+		},
+		{
+			Name:        "cover",
+			Description: "Inspect code coverage",
+			Usage:       "Inspect code coverage",
+			Subcommands: []cli.Command{
+				{
+					Name:        "history",
+					Description: "Shows coverage trend and package changes since previous recorded commit",
+					Usage:       "Shows coverage history",
+					Action:      coverage.HistoryAction,
+					Before:      bootstrap,
+					Flags: []cli.Flag{
+						verboseFlag,
+						coverage.MaxDropFlag,
+						coverage.LimitFlag,
+					},
+				},
+			},
+		},
+		{
+			Name:        "plugins",
+			Description: "Manage project plugins",
+			Usage:       "Manage project plugins",
+			Subcommands: []cli.Command{
+				{
+					Name:        "update",
+					Description: "Fetches plugins declared in manifest and updates plugins lock file",
+					Usage:       "Updates plugins lock file",
+					Action:      maintenance.UpdatePluginsAction,
+					Before:      bootstrap,
+					Flags: []cli.Flag{
+						verboseFlag,
+					},
+				},
+				{
+					Name:        "ls",
+					Description: "Lists plugins in plugins cache",
+					Usage:       "Lists cached plugins",
+					Action:      maintenance.ListPluginsAction,
+					Before:      bootstrap,
+					Flags: []cli.Flag{
+						verboseFlag,
+					},
+				},
+				{
+					Name:        "install",
+					Description: "Fetches plugins from specified URLs or plugins declared in manifest",
+					Usage:       "Pre-fetches plugins for offline use",
+					ArgsUsage:   "[url...]",
+					Action:      maintenance.InstallPluginsAction,
+					Before:      bootstrap,
+					Flags: []cli.Flag{
+						verboseFlag,
+					},
+				},
+				{
+					Name:        "rm",
+					Description: "Removes plugin from plugins cache",
+					Usage:       "Removes cached plugin",
+					ArgsUsage:   "<name or url>...",
+					Action:      maintenance.RemovePluginAction,
+					Before:      bootstrap,
+					Flags: []cli.Flag{
+						verboseFlag,
+					},
+				},
+				{
+					Name:        "prune",
+					Description: "Removes plugins which were not used for a long time",
+					Usage:       "Removes unused cached plugins",
+					Action:      maintenance.PrunePluginsAction,
+					Before:      bootstrap,
+					Flags: []cli.Flag{
+						verboseFlag,
+						maintenance.OlderThanFlag,
+					},
+				},
 			},
 		},
 	}
@@ -17,13 +17,13 @@ var boilerplate = manifest.Manifest{
 		"appVersion": "1.0.0",
 	},
 	Tasks: manifest.TaskSet{
-		"build": manifest.Task{
+		"build": manifest.Task{Jobs: []manifest.Job{
 			{
 				Description: "Build project",
 				ActionName:  "build",
 			},
-		},
-		"cover": manifest.Task{
+		}},
+		"cover": manifest.Task{Jobs: []manifest.Job{
 			{
 				Description: "Check project coverage",
 				ActionName:  "cover",
@@ -35,17 +35,17 @@ var boilerplate = manifest.Manifest{
 					},
 				},
 			},
-		},
-		"clean": manifest.Task{
+		}},
+		"clean": manifest.Task{Jobs: []manifest.Job{
 			{
-				Description: "Remove vendor files",
-				Condition:   "file ./vendor",
-				ActionName:  "shell",
+				Description:    "Remove vendor files",
+				ShellCondition: "file ./vendor",
+				ActionName:     "shell",
 				Params: map[string]interface{}{
 					"command": "rm -rf ./vendor",
 				},
 			},
-		},
+		}},
 	},
 }
 
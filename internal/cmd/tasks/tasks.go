@@ -5,15 +5,17 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
-	"runtime"
+	"path/filepath"
 	"strings"
 
 	"github.com/go-gilbert/gilbert/internal/actions"
+	"github.com/go-gilbert/gilbert/internal/cache"
 	"github.com/go-gilbert/gilbert/internal/log"
 	"github.com/go-gilbert/gilbert/internal/manifest"
 	"github.com/go-gilbert/gilbert/internal/plugins"
+	"github.com/go-gilbert/gilbert/internal/plugins/loader"
+	"github.com/go-gilbert/gilbert/internal/plugins/lockfile"
 	"github.com/go-gilbert/gilbert/internal/runner"
-	"github.com/go-gilbert/gilbert/internal/scope"
 
 	"github.com/urfave/cli"
 )
@@ -22,6 +24,12 @@ const (
 	// OverrideVarFlag is flag name for custom variable values
 	OverrideVarFlag = "var"
 
+	// JobsFlag is flag name for max count of actions running at the same time
+	JobsFlag = "jobs"
+
+	// CacheURLFlag is flag name for remote job outputs cache URL
+	CacheURLFlag = "cache-url"
+
 	varDelimiter = "="
 	paramsCount  = 2
 )
@@ -38,6 +46,10 @@ func RunTask(c *cli.Context) (err error) {
 	}
 
 	task := c.Args()[0]
+	maxParallel := c.Int(JobsFlag)
+	if maxParallel < 0 {
+		return fmt.Errorf("invalid --%s value: %d", JobsFlag, maxParallel)
+	}
 
 	// Get working dir and read manifest
 	cwd, err := os.Getwd()
@@ -52,18 +64,29 @@ func RunTask(c *cli.Context) (err error) {
 
 	// Prepare context and import plugins
 	ctx, cancelFn := context.WithCancel(context.Background())
+	handlers := runner.NewHandlerSet(actions.BuiltinHandlers)
 
-	if err := importProjectPlugins(ctx, man, cwd); err != nil {
+	loaded, err := importProjectPlugins(ctx, man, cwd, handlers)
+	defer closePlugins(loaded)
+	if err != nil {
 		cancelFn()
 		return wrapManifestError(err)
 	}
 
-	// TODO: inject plugins
+	outputsCache, err := cache.NewDefault(c.String(CacheURLFlag))
+	if err != nil {
+		cancelFn()
+		return err
+	}
+
 	cfg := runner.Config{
 		Logger:   log.Default,
-		Handlers: runner.NewHandlerSet(actions.BuiltinHandlers),
+		Handlers: handlers,
 		Manifest: man,
 		WorkDir:  cwd,
+
+		MaxParallel: maxParallel,
+		Cache:       outputsCache,
 	}
 	tr := runner.NewTaskRunner(cfg)
 	tr.SetContext(ctx, cancelFn)
@@ -108,29 +131,40 @@ func getOverrideVars(c *cli.Context) manifest.Vars {
 	return out
 }
 
-func importProjectPlugins(ctx context.Context, m *manifest.Manifest, cwd string) error {
-	if len(m.Plugins) == 0 {
-		return nil
+// importProjectPlugins starts plugins declared in manifest and registers their actions.
+//
+// Plugins are verified using lock file.
+// Returns a list of started plugins even if error occurred.
+func importProjectPlugins(ctx context.Context, m *manifest.Manifest, cwd string, handlers *runner.HandlerSet) ([]*loader.Plugin, error) {
+	urls, err := plugins.ExpandURLs(m, cwd)
+	if err != nil || len(urls) == 0 {
+		return nil, err
 	}
 
-	if runtime.GOOS == "windows" {
-		log.Default.Warn("Warning: plugins currently are not supported on this platform")
-		return nil
+	lock, err := lockfile.Load(lockfile.Path(filepath.Dir(m.Location())))
+	if err != nil {
+		return nil, err
 	}
 
-	s := scope.CreateScope(m.Parser, cwd, m.Vars)
-	for _, uri := range m.Plugins {
-		expanded, err := s.ExpandVariables(uri)
+	loaded := make([]*loader.Plugin, 0, len(urls))
+	for _, uri := range urls {
+		p, err := plugins.Import(ctx, uri, cwd, lock, handlers)
 		if err != nil {
-			return fmt.Errorf("failed to load plugins from manifest, %s", err)
+			return loaded, err
 		}
 
-		if err := plugins.Import(ctx, expanded); err != nil {
-			return err
-		}
+		loaded = append(loaded, p)
 	}
 
-	return nil
+	return loaded, nil
+}
+
+func closePlugins(loaded []*loader.Plugin) {
+	for _, p := range loaded {
+		if err := p.Close(); err != nil {
+			log.Default.Warnf("Failed to stop plugin %q: %s", p.Name(), err)
+		}
+	}
 }
 
 func handleShutdown(cancelFn context.CancelFunc) {
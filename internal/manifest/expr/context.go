@@ -15,7 +15,23 @@ type ValueResolver interface {
 	Values() any
 }
 
+// SourceResolver is optional ValueResolver extension which reports where variables were defined.
+//
+// Variable sources are used in error messages.
+type SourceResolver interface {
+	// VarSource returns description of place where variable was defined (e.g. manifest file or job).
+	VarSource(varName string) (string, bool)
+}
+
 type EvalContext struct {
 	CommandProcessor CommandProcessor
 	Env              ValueResolver
+
+	// WorkDir is directory used to resolve relative paths by expression functions (e.g. "file").
+	//
+	// Process working directory is used if empty.
+	WorkDir string
+
+	// varChain is list of variables which are being expanded
+	varChain []string
 }
@@ -3,6 +3,8 @@ package expr
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 )
 
 var (
@@ -50,3 +52,55 @@ func (err ExpressionError) Unwrap() error {
 func isUnterminatedErr(err *ExpressionError) bool {
 	return errors.Is(err.Err, ErrUnterminatedExpression)
 }
+
+// VarCycleError is returned when variable refers to itself directly or through other variables
+type VarCycleError struct {
+	// Chain is list of variables in reference order.
+	//
+	// The first and the last items are the same variable.
+	Chain []string
+
+	// Sources contains descriptions of places where variables were defined.
+	Sources map[string]string
+}
+
+// newVarCycleError returns error if variable is already present in resolution chain.
+//
+// Variable sources are provided if value resolver implements SourceResolver.
+func newVarCycleError(env ValueResolver, chain []string, varName string) *VarCycleError {
+	i := slices.Index(chain, varName)
+	if i == -1 {
+		return nil
+	}
+
+	err := &VarCycleError{
+		Chain:   append(slices.Clone(chain[i:]), varName),
+		Sources: make(map[string]string),
+	}
+
+	srcResolver, ok := env.(SourceResolver)
+	if !ok {
+		return err
+	}
+
+	for _, name := range err.Chain {
+		if src, ok := srcResolver.VarSource(name); ok {
+			err.Sources[name] = src
+		}
+	}
+
+	return err
+}
+
+func (err VarCycleError) Error() string {
+	sb := strings.Builder{}
+	sb.WriteString("variable cycle: ")
+	sb.WriteString(strings.Join(err.Chain, " -> "))
+	for _, name := range err.Chain[:len(err.Chain)-1] {
+		if src, ok := err.Sources[name]; ok {
+			_, _ = fmt.Fprintf(&sb, "\n  - %s: defined in %s", name, src)
+		}
+	}
+
+	return sb.String()
+}
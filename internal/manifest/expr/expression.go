@@ -3,6 +3,7 @@ package expr
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/expr-lang/expr"
@@ -271,3 +272,57 @@ func decorateExprError(err error, r Range) error {
 	e.ParentRange = r
 	return e
 }
+
+// ConditionRefs describes values referenced by condition expression
+type ConditionRefs struct {
+	// Names is list of identifiers used by expression
+	Names []string
+
+	// Vars is list of "vars" namespace members accessed by name
+	Vars []string
+
+	// AllVars is true if whole "vars" namespace is used (e.g. "len(vars) > 0")
+	AllVars bool
+}
+
+// ConditionReferences returns values referenced by condition expression.
+//
+// Used to resolve only values which are necessary to evaluate a condition.
+func ConditionReferences(str string) (ConditionRefs, error) {
+	tree, err := parser.Parse(str)
+	if err != nil {
+		return ConditionRefs{}, err
+	}
+
+	refs := collectReferences(tree.Node)
+	return ConditionRefs{
+		Names:   refs.names,
+		Vars:    refs.vars,
+		AllVars: refs.allVars,
+	}, nil
+}
+
+// EvalCondition evaluates boolean condition expression against values provided by context.
+//
+// Example:
+//
+//	os == "darwin" && vars.release == "true"
+func EvalCondition(ctx EvalContext, str string) (bool, error) {
+	cfg := evalConfForDir(ctx.WorkDir, expr.Env(ctx.Env.Values()), expr.AsBool())
+	exp, err := NewEvalExpression(NewRange(0, len(str)-1), str, cfg)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := exp.Eval(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	ok, isBool := result.(bool)
+	if !isBool {
+		return false, fmt.Errorf("condition should return a boolean value (got %T)", result)
+	}
+
+	return ok, nil
+}
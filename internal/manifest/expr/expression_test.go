@@ -430,3 +430,51 @@ func must[T any](v T, err error) T {
 
 	return v
 }
+
+func TestEvalCondition(t *testing.T) {
+	cases := map[string]struct {
+		expr    string
+		want    bool
+		wantErr string
+	}{
+		"true condition": {
+			expr: `os == "darwin" && vars.release == "true"`,
+			want: true,
+		},
+		"false condition": {
+			expr: `os == "windows" || !ci`,
+		},
+		"missing variable is empty": {
+			expr: `vars.unknown == ""`,
+			want: true,
+		},
+		"unknown name": {
+			expr:    `foo == "bar"`,
+			wantErr: "unknown name foo",
+		},
+		"non-boolean result": {
+			expr:    `os`,
+			wantErr: "expected bool, but got string",
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			vr := exprmock.NewMockValueResolver(gomock.NewController(t))
+			vr.EXPECT().Values().Return(map[string]any{
+				"os":   "darwin",
+				"ci":   true,
+				"vars": map[string]string{"release": "true"},
+			}).AnyTimes()
+
+			got, err := EvalCondition(EvalContext{Env: vr}, c.expr)
+			if c.wantErr != "" {
+				require.ErrorContains(t, err, c.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, c.want, got)
+		})
+	}
+}
@@ -9,8 +9,14 @@ const (
 	tokenExprStart
 	tokenShellStart
 	tokenEnd
+
+	// tokenEscape is "$$" sequence which represents a literal "$" character
+	tokenEscape
 )
 
+// escapedChar is a value of escape sequence
+const escapedChar = "$"
+
 type tokenPos struct {
 	token    token
 	startPos int
@@ -25,6 +31,14 @@ func findOpenToken(str string, offset int, stopToken byte) tokenPos {
 	for i := offset; i < n; i++ {
 		switch v := str[i]; v {
 		case '$':
+			if i+1 < n && str[i+1] == '$' {
+				return tokenPos{
+					token:    tokenEscape,
+					startPos: i,
+					endPos:   i + 1,
+				}
+			}
+
 			tokenStartPos = i
 		case '{':
 			if tokenStartPos != -1 {
@@ -69,7 +83,22 @@ func findOpenToken(str string, offset int, stopToken byte) tokenPos {
 //	"foo ${bar.baz}"
 //	"2+2=${2+2}!"
 //	"OS is $(uname -s)"
+//	"Price: $$${price}"
+//
+// Sequence "$$" is treated as a literal "$" character.
 func Parse(str string) (Expression, error) {
+	return treeParser{}.parse(str)
+}
+
+// treeParser parses strings into expression tree.
+type treeParser struct {
+	// workDir is directory used by functions to resolve relative paths.
+	//
+	// Process working directory is used if empty.
+	workDir string
+}
+
+func (p treeParser) parse(str string) (Expression, error) {
 	if str == "" {
 		return EmptyExpression{}, nil
 	}
@@ -95,7 +124,7 @@ func Parse(str string) (Expression, error) {
 		}
 
 		// Append adjacent string literal if any.
-		if tok.startPos-1 > start {
+		if tok.startPos > start {
 			strChunk := str[start:tok.startPos]
 			root.Parts = append(
 				root.Parts,
@@ -103,7 +132,13 @@ func Parse(str string) (Expression, error) {
 			)
 		}
 
-		expr, err := consumeToken(rootRng, str, tok)
+		if tok.token == tokenEscape {
+			root.Parts = append(root.Parts, NewLiteralExpression(NewRange(tok.startPos, tok.endPos), escapedChar))
+			start = tok.endPos + 1
+			continue
+		}
+
+		expr, err := p.consumeToken(rootRng, str, tok)
 		if err != nil {
 			return nil, err
 		}
@@ -120,25 +155,19 @@ func Parse(str string) (Expression, error) {
 	return root, nil
 }
 
-func consumeToken(parent Range, str string, pos tokenPos) (Expression, *ExpressionError) {
+func (p treeParser) consumeToken(parent Range, str string, pos tokenPos) (Expression, *ExpressionError) {
 	switch pos.token {
 	case tokenExprStart:
-		return consumeExprToken(parent, str, pos)
+		return p.consumeExprToken(parent, str, pos)
 	case tokenShellStart:
-		return consumeShellToken(parent, str, pos)
+		return p.consumeShellToken(parent, str, pos)
 	default:
 		return nil, newNestedExprError(ErrBadToken, NewRange(pos.startPos, pos.endPos), parent)
 	}
 }
 
-func consumeExprToken(parent Range, str string, pos tokenPos) (Expression, *ExpressionError) {
-	endPos := -1
-	for i := pos.endPos + 1; i < len(str); i++ {
-		if str[i] == '}' {
-			endPos = i
-			break
-		}
-	}
+func (p treeParser) consumeExprToken(parent Range, str string, pos tokenPos) (Expression, *ExpressionError) {
+	endPos := findExprEnd(str, pos.endPos+1)
 
 	if endPos == -1 {
 		return nil, newNestedExprError(
@@ -158,7 +187,7 @@ func consumeExprToken(parent Range, str string, pos tokenPos) (Expression, *Expr
 		)
 	}
 
-	exp, err := NewEvalExpression(tokenRng, content, nil)
+	exp, err := NewEvalExpression(tokenRng, content, evalConfForDir(p.workDir))
 	if err != nil {
 		return nil, newNestedExprError(err, tokenRng, parent)
 	}
@@ -166,7 +195,7 @@ func consumeExprToken(parent Range, str string, pos tokenPos) (Expression, *Expr
 	return exp, nil
 }
 
-func consumeShellToken(parent Range, str string, pos tokenPos) (Expression, *ExpressionError) {
+func (p treeParser) consumeShellToken(parent Range, str string, pos tokenPos) (Expression, *ExpressionError) {
 	se := NewShellExpression(
 		NewRange(pos.startPos, parent.EndCol),
 		make([]Expression, 0, 1),
@@ -183,14 +212,17 @@ func consumeShellToken(parent Range, str string, pos tokenPos) (Expression, *Exp
 		}
 
 		// Append leftovers
-		if tok.startPos-1 > start {
+		if tok.startPos > start {
 			strChunk := str[start:tok.startPos]
 			se.Parts = append(se.Parts, NewLiteralExpression(NewRange(start, tok.startPos-1), strChunk))
 		}
 
 		switch tok.token {
+		case tokenEscape:
+			se.Parts = append(se.Parts, NewLiteralExpression(NewRange(tok.startPos, tok.endPos), escapedChar))
+			start = tok.endPos + 1
 		case tokenExprStart:
-			childExpr, err := consumeExprToken(se.Pos, str, tok)
+			childExpr, err := p.consumeExprToken(se.Pos, str, tok)
 			if err != nil {
 				// Lookup possible shell expression statement end to set correct nested ranges.
 				endPos := strings.IndexByte(str[tok.endPos:], ')')
@@ -229,3 +261,39 @@ func consumeShellToken(parent Range, str string, pos tokenPos) (Expression, *Exp
 		parent,
 	)
 }
+
+// findExprEnd returns position of closing brace of eval expression.
+//
+// Braces inside string literals and nested braces (e.g. map literals) are skipped.
+// Returns -1 if expression is not terminated.
+func findExprEnd(str string, offset int) int {
+	depth := 0
+	var quote byte
+	for i := offset; i < len(str); i++ {
+		c := str[i]
+		if quote != 0 {
+			switch c {
+			case '\\':
+				i++
+			case quote:
+				quote = 0
+			}
+			continue
+		}
+
+		switch c {
+		case '"', '\'', '`':
+			quote = c
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				return i
+			}
+
+			depth--
+		}
+	}
+
+	return -1
+}
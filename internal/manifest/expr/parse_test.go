@@ -130,6 +130,44 @@ func TestParse(t *testing.T) {
 				)
 			},
 		},
+		{
+			label: "single char literal before expression",
+			input: "v${version}",
+			wantFn: func(input string, t *testing.T) Expression {
+				return NewCompositeExpression(
+					NewRange(0, len(input)-1),
+					[]Expression{
+						NewLiteralExpression(NewRange(0, 0), "v"),
+						mustNewEvalExpr(t, NewRange(1, 10), "version", nil),
+					},
+				)
+			},
+		},
+		{
+			label: "escaped dollar sign",
+			input: "$${foo} $(echo $$HOME)",
+			wantFn: func(input string, t *testing.T) Expression {
+				return NewCompositeExpression(
+					NewRange(0, len(input)-1),
+					[]Expression{
+						NewLiteralExpression(NewRange(0, 1), "$"),
+						NewLiteralExpression(NewRange(2, 7), "{foo} "),
+						NewShellExpression(NewRange(8, 21), []Expression{
+							NewLiteralExpression(NewRange(10, 14), "echo "),
+							NewLiteralExpression(NewRange(15, 16), "$"),
+							NewLiteralExpression(NewRange(17, 20), "HOME"),
+						}),
+					},
+				)
+			},
+		},
+		{
+			label: "closing brace inside expression",
+			input: `${ "}" + {"a": 1}.a }`,
+			wantFn: func(input string, t *testing.T) Expression {
+				return mustNewEvalExpr(t, NewRange(0, len(input)-1), ` "}" + {"a": 1}.a `, nil)
+			},
+		},
 
 		// Errors
 		{
@@ -16,8 +16,11 @@ type Parser interface {
 
 // GetParser returns expression parser for a specific language spec version.
 func GetParser(version string) (Parser, error) {
-	if version == "2" {
+	switch version {
+	case "2":
 		return NewSpecV2Parser(), nil
+	case "3":
+		return NewSpecV3Parser(), nil
 	}
 
 	return nil, fmt.Errorf("unsupported language version: %s", version)
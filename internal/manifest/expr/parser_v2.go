@@ -4,10 +4,12 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 )
 
 // FIXME: regex fails to capture escapes with "}" chars (might affect winnt envs).
+// Spec v3 parser (SpecV3Parser) is not affected since it parses expressions into a tree.
 
 var re = regexp.MustCompile(templateRegEx)
 
@@ -66,6 +68,13 @@ func (p SpecV2Parser) ReadString(ctx EvalContext, input string) (result string,
 	var errs []error
 	out := re.ReplaceAllFunc([]byte(input), func(exp []byte) []byte {
 		val, err := p.ReadExpression(ctx, exp)
+		var cycleErr *VarCycleError
+		if errors.As(err, &cycleErr) {
+			// cycle error already contains full variables chain
+			errs = append(errs, cycleErr)
+			return exp
+		}
+
 		if err != nil {
 			errs = append(errs, fmt.Errorf("%w (at %q)", err, string(exp)))
 			return exp
@@ -94,6 +103,10 @@ func (p SpecV2Parser) expandVariable(ctx EvalContext, varName string) (val strin
 		return val, fmt.Errorf("expression cannot be empty")
 	}
 
+	if err := newVarCycleError(ctx.Env, ctx.varChain, varName); err != nil {
+		return val, err
+	}
+
 	// find the var in the scope
 	val, ok := ctx.Env.ValueByName(varName)
 	if !ok {
@@ -103,7 +116,7 @@ func (p SpecV2Parser) expandVariable(ctx EvalContext, varName string) (val strin
 
 	// Parse variable value for nested template expression
 	if p.ContainsExpression(val) {
-		// FIXME: check if variable refers to itself (x = {{x}})
+		ctx.varChain = append(slices.Clone(ctx.varChain), varName)
 		return p.ReadString(ctx, val)
 	}
 
@@ -68,6 +68,30 @@ func TestParser_ReadString(t *testing.T) {
 				}
 			},
 		},
+		"variable refers to itself": {
+			input:     "${foo}",
+			expectErr: "variable cycle: foo -> foo",
+			getContext: func(t *testing.T, ctrl *gomock.Controller) EvalContext {
+				valRes := exprmock.NewMockValueResolver(ctrl)
+				valRes.EXPECT().ValueByName("foo").Return("x-${foo}", true)
+				return EvalContext{
+					Env: valRes,
+				}
+			},
+		},
+		"variables cycle": {
+			input:     "${foo}",
+			expectErr: "variable cycle: bar -> baz -> bar",
+			getContext: func(t *testing.T, ctrl *gomock.Controller) EvalContext {
+				valRes := exprmock.NewMockValueResolver(ctrl)
+				valRes.EXPECT().ValueByName("foo").Return("${bar}", true)
+				valRes.EXPECT().ValueByName("bar").Return("${baz}", true)
+				valRes.EXPECT().ValueByName("baz").Return("${bar}", true)
+				return EvalContext{
+					Env: valRes,
+				}
+			},
+		},
 		"var is undefined": {
 			input:     "${foo.bar}",
 			expectErr: `"foo.bar" is not defined`,
@@ -0,0 +1,305 @@
+package expr
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/expr-lang/expr/ast"
+)
+
+// varsNamespace is identifier to access all variables in expressions (e.g. "vars.foo")
+const varsNamespace = "vars"
+
+// SpecV3Parser implements expression parsing for v3 spec.
+//
+// Strings are parsed into expression tree by Parse, "${...}" blocks are evaluated by expr-lang
+// and support operators, indexing and function calls:
+//
+//	"${ a + b }"
+//	"${ vars.list[0] }"
+//	"${ upper(name) }"
+//
+// Variables are strings, except numbers and lists or maps declared in manifest,
+// which are available in expressions with their types.
+//
+// Variables referenced by expression are resolved lazily, so shell expressions
+// inside unused variables are not evaluated.
+type SpecV3Parser struct{}
+
+// NewSpecV3Parser creates a new processor instance for language spec v3
+func NewSpecV3Parser() SpecV3Parser {
+	return SpecV3Parser{}
+}
+
+// ReadString parses and evaluates expressions inside the string
+func (p SpecV3Parser) ReadString(ctx EvalContext, str string) (string, error) {
+	out, err := newVarResolver(ctx).readString(str)
+	return string(out), err
+}
+
+// ContainsExpression checks if passed string contains template expressions or escape sequences
+func (p SpecV3Parser) ContainsExpression(str string) bool {
+	return findOpenToken(str, 0, 0).token != tokenEmpty
+}
+
+// ReadExpression evaluates an expression string
+func (p SpecV3Parser) ReadExpression(ctx EvalContext, exp []byte) ([]byte, error) {
+	return newVarResolver(ctx).readString(string(exp))
+}
+
+// resolvedValues is a set of variables resolved for expression evaluation
+type resolvedValues map[string]any
+
+func (v resolvedValues) ValueByName(varName string) (string, bool) {
+	val, ok := v[varName]
+	if !ok {
+		return "", false
+	}
+
+	str, err := valueToString(val)
+	return str, err == nil
+}
+
+func (v resolvedValues) Values() any {
+	return map[string]any(v)
+}
+
+// varResolver resolves variables referenced by expressions.
+//
+// Variable values may contain expressions too, which are evaluated recursively.
+type varResolver struct {
+	ctx      EvalContext
+	raw      map[string]string
+	resolved map[string]string
+
+	// chain is list of variables which are being resolved now
+	chain []string
+}
+
+func newVarResolver(ctx EvalContext) *varResolver {
+	raw, _ := ctx.Env.Values().(map[string]string)
+	return &varResolver{
+		ctx:      ctx,
+		raw:      raw,
+		resolved: make(map[string]string, len(raw)),
+	}
+}
+
+func (r *varResolver) readString(str string) ([]byte, error) {
+	exp, err := treeParser{workDir: r.ctx.WorkDir}.parse(str)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exp.Evaluable() {
+		return exp.String(r.ctx)
+	}
+
+	values := make(resolvedValues)
+	if err := r.collect(values, exp); err != nil {
+		return nil, err
+	}
+
+	return exp.String(EvalContext{
+		CommandProcessor: r.ctx.CommandProcessor,
+		Env:              values,
+		WorkDir:          r.ctx.WorkDir,
+	})
+}
+
+// collect resolves variables referenced by expression into values
+func (r *varResolver) collect(values resolvedValues, exp Expression) error {
+	switch t := exp.(type) {
+	case *CompositeExpression:
+		return r.collectParts(values, t.Parts)
+	case *ShellExpression:
+		return r.collectParts(values, t.Parts)
+	case *EvalExpression:
+		refs := collectReferences(t.AST.Node)
+		for _, name := range refs.names {
+			if _, ok := values[name]; ok && name != varsNamespace {
+				continue
+			}
+
+			if err := r.collectValue(values, name, refs, t); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *varResolver) collectParts(values resolvedValues, parts []Expression) error {
+	for _, part := range parts {
+		if err := r.collect(values, part); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *varResolver) collectValue(values resolvedValues, name string, refs references, exp *EvalExpression) error {
+	if _, ok := r.raw[name]; ok {
+		val, err := r.resolve(name)
+		if err != nil {
+			return decorateExprError(err, exp.Range())
+		}
+
+		values[name] = TypedValue(val)
+		return nil
+	}
+
+	if name == varsNamespace {
+		if err := r.collectVars(values, refs); err != nil {
+			return decorateExprError(err, exp.Range())
+		}
+
+		return nil
+	}
+
+	// identifier might be a function
+	if _, ok := exp.EvalConfig.Functions[name]; ok {
+		return nil
+	}
+
+	if _, ok := exp.EvalConfig.Builtins[name]; ok {
+		return nil
+	}
+
+	return newExprError(fmt.Errorf("%q is not defined", name), exp.Range())
+}
+
+// resolve returns variable value with evaluated expressions
+func (r *varResolver) resolve(name string) (string, error) {
+	if val, ok := r.resolved[name]; ok {
+		return val, nil
+	}
+
+	if err := newVarCycleError(r.ctx.Env, r.chain, name); err != nil {
+		return "", err
+	}
+
+	r.chain = append(r.chain, name)
+	defer func() {
+		r.chain = r.chain[:len(r.chain)-1]
+	}()
+
+	val, err := r.readString(r.raw[name])
+	if err != nil {
+		return "", err
+	}
+
+	r.resolved[name] = string(val)
+	return string(val), nil
+}
+
+// collectVars resolves variables accessed through "vars" namespace.
+//
+// Only accessed members are resolved, all variables are resolved only
+// if namespace is used as a value (e.g. "len(vars)").
+func (r *varResolver) collectVars(values resolvedValues, refs references) error {
+	vars, ok := values[varsNamespace].(map[string]any)
+	if !ok {
+		vars = make(map[string]any)
+		values[varsNamespace] = vars
+	}
+
+	names := refs.vars
+	if refs.allVars {
+		names = make([]string, 0, len(r.raw))
+		for k := range r.raw {
+			names = append(names, k)
+		}
+
+		sort.Strings(names)
+	}
+
+	for _, name := range names {
+		if _, ok := vars[name]; ok {
+			continue
+		}
+
+		if _, ok := r.raw[name]; !ok {
+			continue
+		}
+
+		val, err := r.resolve(name)
+		if err != nil {
+			return err
+		}
+
+		vars[name] = TypedValue(val)
+	}
+
+	return nil
+}
+
+// references is a set of identifiers referenced by expression
+type references struct {
+	// names is list of external identifiers
+	names []string
+
+	// vars is list of "vars" namespace members accessed by name (e.g. "vars.foo")
+	vars []string
+
+	// allVars is true if "vars" namespace is used as a value (e.g. "len(vars)")
+	allVars bool
+}
+
+// identifierCollector collects names of identifiers referenced by expression
+type identifierCollector struct {
+	names    []string
+	declared map[string]struct{}
+
+	// varsRefs is count of "vars" identifiers
+	varsRefs int
+	members  []string
+}
+
+func (c *identifierCollector) Visit(node *ast.Node) {
+	switch n := (*node).(type) {
+	case *ast.IdentifierNode:
+		c.names = append(c.names, n.Value)
+		if n.Value == varsNamespace {
+			c.varsRefs++
+		}
+	case *ast.VariableDeclaratorNode:
+		c.declared[n.Name] = struct{}{}
+	case *ast.MemberNode:
+		if ns, prop, ok := memberName(n); ok && ns == varsNamespace {
+			c.members = append(c.members, prop)
+		}
+	}
+}
+
+// collectReferences returns identifiers used in expression.
+//
+// Variables declared inside expression using "let" are ignored.
+func collectReferences(node ast.Node) references {
+	c := &identifierCollector{declared: make(map[string]struct{})}
+	ast.Walk(&node, c)
+
+	refs := references{
+		names:   make([]string, 0, len(c.names)),
+		vars:    c.members,
+		allVars: c.varsRefs > len(c.members),
+	}
+
+	seen := make(map[string]struct{}, len(c.names))
+	for _, name := range c.names {
+		if _, ok := c.declared[name]; ok {
+			continue
+		}
+
+		if _, ok := seen[name]; ok {
+			continue
+		}
+
+		seen[name] = struct{}{}
+		refs.names = append(refs.names, name)
+	}
+
+	return refs
+}
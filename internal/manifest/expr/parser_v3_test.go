@@ -0,0 +1,200 @@
+package expr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-gilbert/gilbert/internal/manifest/expr/exprmock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSpecV3Parser_ReadString(t *testing.T) {
+	cases := map[string]struct {
+		input     string
+		vars      map[string]string
+		commands  map[string]string
+		expect    string
+		expectErr string
+	}{
+		"literal string": {
+			input:  "hello world",
+			expect: "hello world",
+		},
+		"expand variable": {
+			input:  "v${version}!",
+			vars:   map[string]string{"version": "1.0.0"},
+			expect: "v1.0.0!",
+		},
+		"evaluate operators": {
+			input:  "${ a + b } ${ a == 1 ? 'yes' : 'no' }",
+			vars:   map[string]string{"a": "1", "b": "2"},
+			expect: "3 yes",
+		},
+		"concatenate strings": {
+			input:  "${ a + b } ${ a + string(c) }",
+			vars:   map[string]string{"a": "v", "b": "1.10", "c": "2"},
+			expect: "v1.10 v2",
+		},
+		"arithmetic with numbers": {
+			input:  "${ total * 2 } ${ ratio + 1 } ${ -offset }",
+			vars:   map[string]string{"total": "21", "ratio": "0.5", "offset": "-3"},
+			expect: "42 1.5 3",
+		},
+		"keep formatting of non-canonical numbers": {
+			input:  "${ version } ${ id }",
+			vars:   map[string]string{"version": "1.10", "id": "007"},
+			expect: "1.10 007",
+		},
+		"index lists": {
+			input:  "${ list[0] } ${ vars.list[1] } ${ len(list) } ${ join(list, ',') }",
+			vars:   map[string]string{"list": `["a","b"]`},
+			expect: "a b 2 a,b",
+		},
+		"access maps": {
+			input:  "${ target.os }/${ vars.target['arch'] } ${ target.tags[0] }",
+			vars:   map[string]string{"target": `{"os":"linux","arch":"amd64","tags":["netgo"]}`},
+			expect: "linux/amd64 netgo",
+		},
+		"print lists": {
+			input:  "${ list }",
+			vars:   map[string]string{"list": `[1,"a"]`},
+			expect: `[1,"a"]`,
+		},
+		"index list with expressions": {
+			input: "${ list[0] }",
+			vars: map[string]string{
+				"list": `["${name}"]`,
+				"name": "foo",
+			},
+			expect: "foo",
+		},
+		"access vars namespace": {
+			input:  "${ vars.name[0:3] }-${ len(vars) }",
+			vars:   map[string]string{"name": "gilbert", "os": "linux"},
+			expect: "gil-2",
+		},
+		"call builtin functions": {
+			input:  `${ upper(name) }`,
+			vars:   map[string]string{"name": "foo"},
+			expect: "FOO",
+		},
+		"braces in expression": {
+			input:  `${ {"a": "}"}.a }${ name }`,
+			vars:   map[string]string{"name": "foo"},
+			expect: "}foo",
+		},
+		"declared variables": {
+			input:  `${ let x = "foo"; x + name }`,
+			vars:   map[string]string{"name": "bar"},
+			expect: "foobar",
+		},
+		"escape dollar sign": {
+			input: "$$HOME $${foo} $$$$ $(echo $$PATH)",
+			commands: map[string]string{
+				"echo $PATH": "/bin",
+			},
+			expect: "$HOME ${foo} $$ /bin",
+		},
+		"resolve only accessed vars members": {
+			input: "${ vars.name } ${ vars['os'] }",
+			vars: map[string]string{
+				"name":  "foo",
+				"os":    "linux",
+				"a":     "${b}",
+				"b":     "${a}",
+				"shell": "$(exit 1)",
+			},
+			expect: "foo linux",
+		},
+		"resolve all vars if namespace is used as value": {
+			input: "${ vars.name } ${ len(vars) }",
+			vars: map[string]string{
+				"name": "foo",
+				"a":    "${b}",
+				"b":    "${a}",
+			},
+			expectErr: "variable cycle: a -> b -> a",
+		},
+		"resolve nested variables": {
+			input: "${target}",
+			vars: map[string]string{
+				"target": "${os}-${arch}",
+				"os":     "$(uname -s)",
+				"arch":   "amd64",
+				"unused": "$(exit 1)",
+			},
+			commands: map[string]string{
+				"uname -s": "linux",
+			},
+			expect: "linux-amd64",
+		},
+		"shell expression with variables": {
+			input:    "$(git log -n ${count})",
+			vars:     map[string]string{"count": "1"},
+			commands: map[string]string{"git log -n 1": "commit"},
+			expect:   "commit",
+		},
+		"undefined variable": {
+			input:     "foo ${bar}",
+			expectErr: `"bar" is not defined (at 4:9)`,
+		},
+		"recursive variable": {
+			input:     "${a}",
+			vars:      map[string]string{"a": "${b}", "b": "${a}"},
+			expectErr: "variable cycle: a -> b -> a",
+		},
+		"syntax error": {
+			input:     "${ a + }",
+			expectErr: "unexpected token EOF",
+		},
+		"failed shell expression": {
+			input:     "$(exit 1)",
+			expectErr: "exit status 1",
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			cmdProc := exprmock.NewMockCommandProcessor(ctrl)
+			cmdProc.EXPECT().EvalCommand(gomock.Any()).DoAndReturn(func(cmd string) ([]byte, error) {
+				out, ok := c.commands[cmd]
+				if !ok {
+					return nil, errors.New("exit status 1")
+				}
+
+				return []byte(out), nil
+			}).AnyTimes()
+
+			valRes := exprmock.NewMockValueResolver(ctrl)
+			valRes.EXPECT().Values().Return(c.vars).AnyTimes()
+
+			got, err := NewSpecV3Parser().ReadString(EvalContext{CommandProcessor: cmdProc, Env: valRes}, c.input)
+			if c.expectErr != "" {
+				require.ErrorContains(t, err, c.expectErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, c.expect, got)
+		})
+	}
+}
+
+func TestSpecV3Parser_ContainsExpression(t *testing.T) {
+	p := NewSpecV3Parser()
+	require.False(t, p.ContainsExpression("foo $ bar"))
+	require.True(t, p.ContainsExpression("foo ${bar}"))
+	require.True(t, p.ContainsExpression("foo $(bar)"))
+	require.True(t, p.ContainsExpression("foo $$bar"))
+}
+
+func TestGetParser(t *testing.T) {
+	p, err := GetParser("3")
+	require.NoError(t, err)
+	require.Equal(t, NewSpecV3Parser(), p)
+
+	_, err = GetParser("1")
+	require.EqualError(t, err, "unsupported language version: 1")
+}
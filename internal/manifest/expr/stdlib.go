@@ -0,0 +1,306 @@
+package expr
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/ast"
+	"github.com/expr-lang/expr/conf"
+	"github.com/go-gilbert/gilbert/internal/support/git"
+)
+
+// defaultTimeLayout is default time format of "now" function
+const defaultTimeLayout = time.RFC3339
+
+var (
+	// gitOutput runs git command in a directory and returns its output.
+	//
+	// Process working directory is used if directory is empty.
+	gitOutput = func(dir string, args ...string) (string, error) {
+		out, err := git.Output(context.Background(), dir, args...)
+		return strings.TrimSpace(string(out)), err
+	}
+
+	// timeNow returns current time
+	timeNow = time.Now
+
+	// stdlibByDir contains functions tables by working directory
+	stdlibByDir sync.Map
+)
+
+// stdlib is a set of functions available in expressions which use process working directory
+var stdlib = newStdlib("")
+
+// stdlibForDir returns a set of functions which resolve relative paths against specified directory.
+//
+// Functions table is created once per directory.
+func stdlibForDir(dir string) conf.FunctionsTable {
+	if dir == "" {
+		return stdlib
+	}
+
+	if v, ok := stdlibByDir.Load(dir); ok {
+		return v.(conf.FunctionsTable)
+	}
+
+	v, _ := stdlibByDir.LoadOrStore(dir, newStdlib(dir))
+	return v.(conf.FunctionsTable)
+}
+
+// newStdlib creates a set of functions available in expressions.
+//
+// File paths are relative to specified directory or process working directory if it's empty.
+// Namespaced functions (e.g. "git.commit") are called using member syntax: "git.commit()".
+func newStdlib(dir string) conf.FunctionsTable {
+	return newFunctionsTable(
+		expr.Function("env", fnEnv,
+			new(func(string) string),
+			new(func(string, string) string),
+		),
+		expr.Function("file", func(params ...any) (any, error) {
+			return fnFile(dir, params...)
+		}, new(func(string) string)),
+		expr.Function("sha256", func(params ...any) (any, error) {
+			return fnSHA256(dir, params...)
+		}, new(func(string) string)),
+		expr.Function("glob", func(params ...any) (any, error) {
+			return fnGlob(dir, params...)
+		}, new(func(string) []string)),
+		expr.Function("now", fnNow,
+			new(func() string),
+			new(func(string) string),
+		),
+		expr.Function("os", func(...any) (any, error) {
+			return runtime.GOOS, nil
+		}, new(func() string)),
+		expr.Function("arch", func(...any) (any, error) {
+			return runtime.GOARCH, nil
+		}, new(func() string)),
+		expr.Function("default", fnDefault),
+		expr.Function("semver.bump", fnSemverBump,
+			new(func(string) string),
+			new(func(string, string) string),
+		),
+		expr.Function("git.commit", func(...any) (any, error) {
+			return gitOutput(dir, "rev-parse", "HEAD")
+		}, new(func() string)),
+		expr.Function("git.tag", func(...any) (any, error) {
+			return gitOutput(dir, "describe", "--tags", "--abbrev=0")
+		}, new(func() string)),
+		expr.Function("git.dirty", func(...any) (any, error) {
+			out, err := gitOutput(dir, "status", "--porcelain")
+			return out != "", err
+		}, new(func() bool)),
+	)
+}
+
+// newFunctionsTable creates table of functions declared by options
+func newFunctionsTable(fns ...expr.Option) conf.FunctionsTable {
+	c := conf.CreateNew()
+	for _, fn := range fns {
+		fn(c)
+	}
+
+	return c.Functions
+}
+
+// envNamespace is identifier to access environment variables as "env.NAME", which is the same as env("NAME")
+const envNamespace = "env"
+
+// namespacePatcher replaces namespaced function calls like "git.commit()"
+// with calls of functions registered as "git.commit".
+//
+// Environment variables access like "env.HOME" is replaced with "env" function call.
+type namespacePatcher struct {
+	functions conf.FunctionsTable
+}
+
+func (p namespacePatcher) Visit(node *ast.Node) {
+	switch n := (*node).(type) {
+	case *ast.CallNode:
+		ns, prop, ok := memberName(n.Callee)
+		if !ok {
+			return
+		}
+
+		name := ns + "." + prop
+		if _, ok := p.functions[name]; ok {
+			ast.Patch(&n.Callee, &ast.IdentifierNode{Value: name})
+		}
+	case *ast.MemberNode:
+		ns, prop, ok := memberName(n)
+		if !ok || ns != envNamespace {
+			return
+		}
+
+		if _, ok := p.functions[envNamespace]; ok {
+			ast.Patch(node, &ast.CallNode{
+				Callee:    &ast.IdentifierNode{Value: envNamespace},
+				Arguments: []ast.Node{&ast.StringNode{Value: prop}},
+			})
+		}
+	}
+}
+
+// memberName returns identifier and property names of member access node like "foo.bar"
+func memberName(node ast.Node) (string, string, bool) {
+	member, ok := node.(*ast.MemberNode)
+	if !ok {
+		return "", "", false
+	}
+
+	ns, ok := member.Node.(*ast.IdentifierNode)
+	if !ok {
+		return "", "", false
+	}
+
+	prop, ok := member.Property.(*ast.StringNode)
+	if !ok {
+		return "", "", false
+	}
+
+	return ns.Value, prop.Value, true
+}
+
+// env("NAME", "default") returns environment variable value or default value if variable is empty
+func fnEnv(params ...any) (any, error) {
+	val := os.Getenv(params[0].(string))
+	if val == "" && len(params) > 1 {
+		return params[1].(string), nil
+	}
+
+	return val, nil
+}
+
+// resolvePath returns path relative to base directory
+func resolvePath(dir, path string) string {
+	if dir == "" || filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(dir, path)
+}
+
+// file("VERSION") returns file contents without leading and trailing spaces
+func fnFile(dir string, params ...any) (any, error) {
+	data, err := os.ReadFile(resolvePath(dir, params[0].(string)))
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// sha256("go.sum") returns hex-encoded SHA256 checksum of a file
+func fnSHA256(dir string, params ...any) (any, error) {
+	data, err := os.ReadFile(resolvePath(dir, params[0].(string)))
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// glob("cmd/*") returns sorted list of files matching the pattern.
+//
+// Paths of relative pattern matches are relative to base directory.
+func fnGlob(dir string, params ...any) (any, error) {
+	pattern := params[0].(string)
+	matches, err := filepath.Glob(resolvePath(dir, pattern))
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]string, 0, len(matches))
+	for _, match := range matches {
+		if dir != "" && !filepath.IsAbs(pattern) {
+			if rel, err := filepath.Rel(dir, match); err == nil {
+				match = rel
+			}
+		}
+
+		out = append(out, match)
+	}
+
+	return out, nil
+}
+
+// now("2006-01-02") returns current time in specified format
+func fnNow(params ...any) (any, error) {
+	layout := defaultTimeLayout
+	if len(params) > 0 {
+		layout = params[0].(string)
+	}
+
+	return timeNow().Format(layout), nil
+}
+
+// default(value, fallback) returns fallback value if value is nil or empty string
+func fnDefault(params ...any) (any, error) {
+	if len(params) != 2 {
+		return nil, fmt.Errorf("default: expected 2 arguments (got %d)", len(params))
+	}
+
+	if params[0] == nil || params[0] == "" {
+		return params[1], nil
+	}
+
+	return params[0], nil
+}
+
+// semver.bump("v1.2.3", "minor") increments major, minor or patch (default) version component.
+//
+// Pre-release and build metadata are dropped.
+func fnSemverBump(params ...any) (any, error) {
+	part := "patch"
+	if len(params) > 1 {
+		part = params[1].(string)
+	}
+
+	version := params[0].(string)
+	prefix := ""
+	if strings.HasPrefix(version, "v") {
+		prefix = "v"
+	}
+
+	core, _, _ := strings.Cut(strings.TrimPrefix(version, prefix), "+")
+	core, _, _ = strings.Cut(core, "-")
+	chunks := strings.Split(core, ".")
+	if len(chunks) != 3 {
+		return nil, fmt.Errorf("semver.bump: invalid version %q", version)
+	}
+
+	nums := make([]int, len(chunks))
+	for i, c := range chunks {
+		n, err := strconv.Atoi(c)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("semver.bump: invalid version %q", version)
+		}
+
+		nums[i] = n
+	}
+
+	switch part {
+	case "major":
+		nums = []int{nums[0] + 1, 0, 0}
+	case "minor":
+		nums = []int{nums[0], nums[1] + 1, 0}
+	case "patch":
+		nums[2]++
+	default:
+		return nil, fmt.Errorf("semver.bump: unknown version part %q (expected major, minor or patch)", part)
+	}
+
+	return fmt.Sprintf("%s%d.%d.%d", prefix, nums[0], nums[1], nums[2]), nil
+}
@@ -0,0 +1,140 @@
+package expr
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-gilbert/gilbert/internal/manifest/expr/exprmock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestStdlib(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "VERSION"), []byte("1.2.3\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("foo"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.txt"), []byte("bar"), 0644))
+	t.Setenv("GILBERT_TEST_VAR", "foo")
+	t.Setenv("GILBERT_EMPTY_VAR", "")
+
+	gitCommands := map[string]string{
+		"rev-parse HEAD":             "a1b2c3",
+		"describe --tags --abbrev=0": "v1.0.0",
+		"status --porcelain":         " M go.mod",
+	}
+
+	origGitOutput, origTimeNow := gitOutput, timeNow
+	t.Cleanup(func() {
+		gitOutput, timeNow = origGitOutput, origTimeNow
+	})
+
+	gitOutput = func(d string, args ...string) (string, error) {
+		require.Equal(t, dir, d)
+		out, ok := gitCommands[strings.Join(args, " ")]
+		if !ok {
+			return "", errors.New("unexpected git command")
+		}
+
+		return out, nil
+	}
+
+	timeNow = func() time.Time {
+		return time.Date(2024, 3, 1, 10, 20, 30, 0, time.UTC)
+	}
+
+	cases := map[string]struct {
+		input     string
+		vars      map[string]string
+		expect    string
+		expectErr string
+	}{
+		"env": {
+			input:  `${ env("GILBERT_TEST_VAR") }`,
+			expect: "foo",
+		},
+		"env member access": {
+			input:  `${ env.GILBERT_TEST_VAR }`,
+			expect: "foo",
+		},
+		"env default value": {
+			input:  `${ env("GILBERT_EMPTY_VAR", "bar") }`,
+			expect: "bar",
+		},
+		"file": {
+			input:  `v${ file("VERSION") }`,
+			expect: "v1.2.3",
+		},
+		"file with absolute path": {
+			input:  "${ file(path) }",
+			vars:   map[string]string{"path": filepath.Join(dir, "VERSION")},
+			expect: "1.2.3",
+		},
+		"file not exists": {
+			input:     `${ file("missing") }`,
+			expectErr: "no such file or directory",
+		},
+		"sha256": {
+			input:  `${ sha256("a.txt") }`,
+			expect: "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae",
+		},
+		"glob": {
+			input:  `${ join(glob("*.txt"), ",") }`,
+			expect: "a.txt,b.txt",
+		},
+		"glob without matches": {
+			input:  `${ len(glob("*.go")) }`,
+			expect: "0",
+		},
+		"now": {
+			input:  `${ now() } ${ now("2006-01-02") }`,
+			expect: "2024-03-01T10:20:30Z 2024-03-01",
+		},
+		"os and arch": {
+			input:  `${ os() }/${ arch() }`,
+			expect: runtime.GOOS + "/" + runtime.GOARCH,
+		},
+		"default": {
+			input:  `${ default(a, "foo") } ${ default(b, "foo") }`,
+			vars:   map[string]string{"a": "", "b": "bar"},
+			expect: "foo bar",
+		},
+		"semver bump": {
+			input:  `${ semver.bump("1.2.3") } ${ semver.bump("v1.2.3", "minor") } ${ semver.bump("v1.2.3-rc.1+abc", "major") }`,
+			expect: "1.2.4 v1.3.0 v2.0.0",
+		},
+		"semver bump invalid version": {
+			input:     `${ semver.bump("1.2") }`,
+			expectErr: `semver.bump: invalid version "1.2"`,
+		},
+		"semver bump unknown part": {
+			input:     `${ semver.bump("1.2.3", "build") }`,
+			expectErr: `semver.bump: unknown version part "build"`,
+		},
+		"git": {
+			input:  `${ git.commit() } ${ git.tag() } ${ git.dirty() }`,
+			expect: "a1b2c3 v1.0.0 true",
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			valRes := exprmock.NewMockValueResolver(ctrl)
+			valRes.EXPECT().Values().Return(c.vars).AnyTimes()
+
+			got, err := NewSpecV3Parser().ReadString(EvalContext{Env: valRes, WorkDir: dir}, c.input)
+			if c.expectErr != "" {
+				require.ErrorContains(t, err, c.expectErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, c.expect, got)
+		})
+	}
+}
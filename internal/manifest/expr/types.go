@@ -1,8 +1,10 @@
 package expr
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/expr-lang/expr"
 	"github.com/expr-lang/expr/checker"
@@ -18,13 +20,57 @@ func valueToString(v any) (string, error) {
 		return t, nil
 	case bool, uint, uint8, uint16, uint32, uint64, int, int8, int16, int32, int64, float32, float64:
 		return fmt.Sprint(v), nil
+	case []any, []string, map[string]any:
+		data, err := json.Marshal(t)
+		return string(data), err
 	}
 
 	return "", fmt.Errorf("value %#v cannot be converted to a string", v)
 }
 
+// TypedValue converts variable value to a number, list or map.
+//
+// All consumers of variables in expressions should use it to see the same value types.
+// Lists and maps are declared in manifest as YAML and stored as JSON.
+// Numbers are converted only if they are printed back the same way, so "1.10" or "007" remain strings.
+func TypedValue(val string) any {
+	if val == "" {
+		return val
+	}
+
+	switch c := val[0]; {
+	case c == '[' || c == '{':
+		var out any
+		if err := json.Unmarshal([]byte(val), &out); err != nil {
+			return val
+		}
+
+		return out
+	case c == '-' || (c >= '0' && c <= '9'):
+		if n, err := strconv.Atoi(val); err == nil && strconv.Itoa(n) == val {
+			return n
+		}
+
+		if f, err := strconv.ParseFloat(val, 64); err == nil && fmt.Sprint(f) == val {
+			return f
+		}
+	}
+
+	return val
+}
+
 func evalConfWithOptions(opts ...expr.Option) *conf.Config {
+	return evalConfForDir("", opts...)
+}
+
+// evalConfForDir returns evaluation config with functions which resolve relative paths against specified directory
+func evalConfForDir(dir string, opts ...expr.Option) *conf.Config {
 	c := conf.CreateNew()
+	for name, fn := range stdlibForDir(dir) {
+		c.Functions[name] = fn
+	}
+
+	expr.Patch(namespacePatcher{functions: c.Functions})(c)
 	for _, op := range opts {
 		op(c)
 	}
@@ -30,8 +30,16 @@ type Manifest struct {
 	// Mixins is a set of declared mixins
 	Mixins Mixins `yaml:"mixins,omitempty"`
 
+	// Cache enables caching of shell expression results during a run.
+	//
+	// Enabled by default, set to false if commands should be evaluated every time.
+	Cache *bool `yaml:"cache,omitempty"`
+
 	// location is manifest location
 	location string `yaml:"-"`
+
+	// varSources contains locations of imported files by names of variables declared there
+	varSources map[string]string `yaml:"-"`
 }
 
 // Location returns manifest file location, if it was loaded using FromDirectory method
@@ -39,8 +47,45 @@ func (m *Manifest) Location() string {
 	return m.location
 }
 
+// CacheCommands returns whether shell expression results should be reused during a run
+func (m *Manifest) CacheCommands() bool {
+	return m.Cache == nil || *m.Cache
+}
+
+// VarSource returns location of manifest file where variable was declared.
+//
+// Variables of imported files point to imported file location.
+func (m *Manifest) VarSource(varName string) (string, bool) {
+	if _, ok := m.Vars[varName]; !ok {
+		return "", false
+	}
+
+	if src, ok := m.varSources[varName]; ok {
+		return src, true
+	}
+
+	return m.location, m.location != ""
+}
+
 // includeParent includes parent manifest into the current
 func (m *Manifest) includeParent(parent *Manifest) {
+	for k := range parent.Vars {
+		if _, ok := m.Vars[k]; ok {
+			continue
+		}
+
+		src, ok := parent.VarSource(k)
+		if !ok {
+			continue
+		}
+
+		if m.varSources == nil {
+			m.varSources = make(map[string]string)
+		}
+
+		m.varSources[k] = src
+	}
+
 	m.Vars = m.Vars.AppendNew(parent.Vars)
 	if len(parent.Mixins) > 0 {
 		if m.Mixins == nil {
@@ -65,12 +110,12 @@ func (m *Manifest) includeParent(parent *Manifest) {
 
 		// Copy tasks
 		for k, mx := range parent.Tasks {
-			// Skip if mixin with the same name defined in parent
+			// Skip if task with the same name defined in parent
 			if _, ok := m.Tasks[k]; ok {
 				continue
 			}
 
-			m.Tasks[k] = append(m.Tasks[k], mx...)
+			m.Tasks[k] = mx
 		}
 	}
 
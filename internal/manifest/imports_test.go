@@ -1,10 +1,13 @@
 package manifest
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/go-gilbert/gilbert/internal/manifest/expr"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const testFile = "./testdata/a.yaml"
@@ -21,30 +24,33 @@ func TestLoadManifest(t *testing.T) {
 		Vars: Vars{
 			"b": "b0",
 		},
+		varSources: map[string]string{
+			"b": filepath.Join("testdata", "include", "b.yaml"),
+		},
 		Mixins: Mixins{
 			"b11mx": Mixin{
 				Job{ActionName: "build"},
 			},
 		},
 		Tasks: TaskSet{
-			"build": Task{
+			"build": Task{Jobs: []Job{
 				Job{ActionName: "build"},
-			},
-			"b": Task{
+			}},
+			"b": Task{Jobs: []Job{
 				Job{ActionName: "shell"},
-			},
-			"b1": Task{
+			}},
+			"b1": Task{Jobs: []Job{
 				Job{ActionName: "shell"},
-			},
-			"b2": Task{
+			}},
+			"b2": Task{Jobs: []Job{
 				Job{ActionName: "shell"},
-			},
-			"b11": Task{
+			}},
+			"b11": Task{Jobs: []Job{
 				Job{ActionName: "shell"},
-			},
-			"c": Task{
+			}},
+			"c": Task{Jobs: []Job{
 				Job{ActionName: "shell"},
-			},
+			}},
 		},
 	}
 
@@ -54,3 +60,46 @@ func TestLoadManifest(t *testing.T) {
 		assert.Equal(t, expected, *result)
 	}
 }
+
+func TestLoadManifest_Depends(t *testing.T) {
+	result, err := LoadManifest("./testdata/depends.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, TaskSet{
+		"build": Task{Jobs: []Job{
+			Job{ActionName: "build"},
+		}},
+		"release": Task{
+			Depends: []string{"build"},
+			Jobs: []Job{
+				Job{ActionName: "shell"},
+			},
+		},
+	}, result.Tasks)
+	assert.NoError(t, result.Tasks.CheckDependencies("release"))
+}
+
+func TestLoadManifest_WithoutImports(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), FileName)
+	require.NoError(t, os.WriteFile(fileName, []byte("version: \"3\"\ntasks:\n  build:\n  - action: build\n"), 0644))
+
+	result, err := LoadManifest(fileName)
+	require.NoError(t, err)
+	assert.Equal(t, expr.NewSpecV3Parser(), result.Parser)
+}
+
+func TestManifest_VarSource(t *testing.T) {
+	result, err := LoadManifest(testFile)
+	require.NoError(t, err)
+
+	src, ok := result.VarSource("b")
+	require.True(t, ok)
+	require.Equal(t, filepath.Join("testdata", "include", "b.yaml"), src)
+
+	_, ok = result.VarSource("foo")
+	require.False(t, ok)
+
+	result.Vars["foo"] = "bar"
+	src, ok = result.VarSource("foo")
+	require.True(t, ok)
+	require.Equal(t, testFile, src)
+}
@@ -46,9 +46,12 @@ func (p ActionParams) Unmarshal(dest interface{}) error {
 
 // Job represents a single step in task
 type Job struct {
-	// Condition is shell command that should be successful to run specified job
+	// Condition is boolean expression that should be true to run specified job
 	Condition string `yaml:"if,omitempty" mapstructure:"if"`
 
+	// ShellCondition is shell command that should be successful to run specified job
+	ShellCondition string `yaml:"ifShell,omitempty" mapstructure:"ifShell"`
+
 	// Description is job description
 	Description string `yaml:"description,omitempty" mapstructure:"description"`
 
@@ -77,6 +80,25 @@ type Job struct {
 
 	// Params is a set of arguments for the job.
 	Params ActionParams `yaml:"params,omitempty" mapstructure:"params"`
+
+	// Inputs is a list of file globs used by the job.
+	//
+	// Job will be skipped if inputs, outputs, params and variables were not changed since last successful run.
+	Inputs []string `yaml:"inputs,omitempty" mapstructure:"inputs"`
+
+	// Outputs is a list of files or directories produced by the job.
+	Outputs []string `yaml:"outputs,omitempty" mapstructure:"outputs"`
+
+	// Matrix is a set of variable values to run the job once per each combination.
+	Matrix *Matrix `yaml:"matrix,omitempty" mapstructure:"-"`
+
+	// combination is a matrix combination used to create the job
+	combination string
+}
+
+// IsIncremental checks if job can be skipped when it's inputs are unchanged
+func (j *Job) IsIncremental() bool {
+	return len(j.Inputs) > 0
 }
 
 // HasDescription checks if description is available
@@ -86,6 +108,15 @@ func (j *Job) HasDescription() bool {
 
 // FormatDescription returns formatted description string
 func (j *Job) FormatDescription() string {
+	descr := j.description()
+	if j.combination != "" {
+		return descr + " (" + j.combination + ")"
+	}
+
+	return descr
+}
+
+func (j *Job) description() string {
 	if j.Description != "" {
 		return j.Description
 	}
@@ -100,6 +131,28 @@ func (j *Job) FormatDescription() string {
 	return ""
 }
 
+// ExpandMatrix returns a list of jobs for each matrix combination.
+//
+// Combination variables override job variables.
+// If job has no matrix, the job itself is returned.
+func (j Job) ExpandMatrix() []Job {
+	if j.Matrix == nil {
+		return []Job{j}
+	}
+
+	combinations := j.Matrix.Combinations()
+	out := make([]Job, 0, len(combinations))
+	for _, c := range combinations {
+		mj := j
+		mj.Matrix = nil
+		mj.Vars = j.Vars.Append(c)
+		mj.combination = j.Matrix.Format(c)
+		out = append(out, mj)
+	}
+
+	return out
+}
+
 // Type returns job execution type
 //
 // If job has no 'action', 'task' or 'mixin' declaration, ExecEmpty will be returned
@@ -43,6 +43,7 @@ func LoadManifest(path string) (*Manifest, error) {
 	}
 
 	m.location = path
+	m.Parser = exprParser
 
 	// Return as-is if no imports declared
 	if len(m.Imports) == 0 {
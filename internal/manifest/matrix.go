@@ -0,0 +1,219 @@
+package manifest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+const (
+	matrixInclude = "include"
+	matrixExclude = "exclude"
+)
+
+// MatrixVar is a list of values of matrix variable
+type MatrixVar struct {
+	Name   string
+	Values []string
+}
+
+// Matrix is a set of variable values.
+//
+// Job with matrix is started once per each combination of variable values:
+//
+//	- mixin: platform-build
+//	  matrix:
+//	    os: [linux, windows]
+//	    arch: ['386', amd64]
+//	    exclude:
+//	      - os: windows
+//	        arch: '386'
+//	    include:
+//	      - os: darwin
+//	        arch: amd64
+type Matrix struct {
+	// Vars is a list of variables in declaration order
+	Vars []MatrixVar
+
+	// Include is a list of additional combinations
+	Include []Vars
+
+	// Exclude is a list of combinations which should be skipped.
+	//
+	// Combination is skipped if it contains all variables of exclude entry.
+	Exclude []Vars
+}
+
+// UnmarshalYAML implements yaml.InterfaceUnmarshaler
+func (m *Matrix) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var items yaml.MapSlice
+	if err := unmarshal(&items); err != nil {
+		return err
+	}
+
+	*m = Matrix{}
+	for _, item := range items {
+		key := fmt.Sprint(item.Key)
+		var err error
+		switch key {
+		case matrixInclude:
+			m.Include, err = matrixCombinations(item.Value)
+		case matrixExclude:
+			m.Exclude, err = matrixCombinations(item.Value)
+		default:
+			var values []string
+			values, err = matrixValues(item.Value)
+			m.Vars = append(m.Vars, MatrixVar{Name: key, Values: values})
+		}
+
+		if err != nil {
+			return fmt.Errorf("invalid matrix %q value: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// MarshalYAML implements yaml.InterfaceMarshaler
+func (m Matrix) MarshalYAML() (interface{}, error) {
+	out := make(yaml.MapSlice, 0, len(m.Vars)+2)
+	for _, v := range m.Vars {
+		out = append(out, yaml.MapItem{Key: v.Name, Value: v.Values})
+	}
+
+	if len(m.Include) > 0 {
+		out = append(out, yaml.MapItem{Key: matrixInclude, Value: m.Include})
+	}
+
+	if len(m.Exclude) > 0 {
+		out = append(out, yaml.MapItem{Key: matrixExclude, Value: m.Exclude})
+	}
+
+	return out, nil
+}
+
+// Combinations returns all combinations of matrix variables
+func (m Matrix) Combinations() []Vars {
+	combinations := []Vars{{}}
+	for _, v := range m.Vars {
+		next := make([]Vars, 0, len(combinations)*len(v.Values))
+		for _, c := range combinations {
+			for _, val := range v.Values {
+				next = append(next, c.Append(Vars{v.Name: val}))
+			}
+		}
+
+		combinations = next
+	}
+
+	if len(m.Vars) == 0 {
+		combinations = nil
+	}
+
+	out := make([]Vars, 0, len(combinations)+len(m.Include))
+	for _, c := range combinations {
+		if !m.isExcluded(c) {
+			out = append(out, c)
+		}
+	}
+
+	for _, c := range m.Include {
+		out = append(out, c.Clone())
+	}
+
+	return out
+}
+
+// Format returns a human-readable representation of matrix combination
+//
+// Matrix variables are listed in declaration order, other variables are sorted by name.
+func (m Matrix) Format(combination Vars) string {
+	names := make([]string, 0, len(combination))
+	known := make(map[string]bool, len(m.Vars))
+	for _, v := range m.Vars {
+		known[v.Name] = true
+		if _, ok := combination[v.Name]; ok {
+			names = append(names, v.Name)
+		}
+	}
+
+	extra := make([]string, 0, len(combination))
+	for k := range combination {
+		if !known[k] {
+			extra = append(extra, k)
+		}
+	}
+
+	sort.Strings(extra)
+	names = append(names, extra...)
+
+	pairs := make([]string, 0, len(names))
+	for _, k := range names {
+		pairs = append(pairs, k+": "+combination[k])
+	}
+
+	return strings.Join(pairs, ", ")
+}
+
+func (m Matrix) isExcluded(combination Vars) bool {
+	for _, exclude := range m.Exclude {
+		matched := true
+		for k, v := range exclude {
+			if combination[k] != v {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matrixValues(v interface{}) ([]string, error) {
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a list of values, got %T", v)
+	}
+
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		switch item.(type) {
+		case []interface{}, map[string]interface{}, yaml.MapSlice:
+			return nil, fmt.Errorf("expected a scalar value, got %T", item)
+		}
+
+		out = append(out, fmt.Sprint(item))
+	}
+
+	return out, nil
+}
+
+func matrixCombinations(v interface{}) ([]Vars, error) {
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a list of variables, got %T", v)
+	}
+
+	out := make([]Vars, 0, len(list))
+	for _, item := range list {
+		vars, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected a set of variables, got %T", item)
+		}
+
+		c := make(Vars, len(vars))
+		for k, val := range vars {
+			c[k] = fmt.Sprint(val)
+		}
+
+		out = append(out, c)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,101 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatrix_UnmarshalYAML(t *testing.T) {
+	cases := map[string]struct {
+		src  string
+		want Matrix
+		err  string
+	}{
+		"variables in declaration order": {
+			src: "os: [linux, windows]\narch: ['386', amd64]\nversion: [1, 2]\n",
+			want: Matrix{Vars: []MatrixVar{
+				{Name: "os", Values: []string{"linux", "windows"}},
+				{Name: "arch", Values: []string{"386", "amd64"}},
+				{Name: "version", Values: []string{"1", "2"}},
+			}},
+		},
+		"include and exclude": {
+			src: "os: [linux]\ninclude:\n- os: darwin\n  arch: amd64\nexclude:\n- os: linux\n",
+			want: Matrix{
+				Vars:    []MatrixVar{{Name: "os", Values: []string{"linux"}}},
+				Include: []Vars{{"os": "darwin", "arch": "amd64"}},
+				Exclude: []Vars{{"os": "linux"}},
+			},
+		},
+		"invalid variable value": {
+			src: "os: linux\n",
+			err: `invalid matrix "os" value: expected a list of values`,
+		},
+		"invalid include value": {
+			src: "include: [linux]\n",
+			err: `invalid matrix "include" value: expected a set of variables`,
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			var got Matrix
+			err := yaml.Unmarshal([]byte(c.src), &got)
+			if c.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, c.want, got)
+		})
+	}
+}
+
+func TestMatrix_Combinations(t *testing.T) {
+	m := Matrix{
+		Vars: []MatrixVar{
+			{Name: "os", Values: []string{"linux", "windows"}},
+			{Name: "arch", Values: []string{"386", "amd64"}},
+		},
+		Include: []Vars{{"os": "darwin", "arch": "amd64", "ext": ""}},
+		Exclude: []Vars{{"os": "windows", "arch": "386"}},
+	}
+
+	want := []Vars{
+		{"os": "linux", "arch": "386"},
+		{"os": "linux", "arch": "amd64"},
+		{"os": "windows", "arch": "amd64"},
+		{"os": "darwin", "arch": "amd64", "ext": ""},
+	}
+	got := m.Combinations()
+	require.Equal(t, want, got)
+	assert.Equal(t, "os: darwin, arch: amd64, ext: ", m.Format(got[3]))
+}
+
+func TestJob_ExpandMatrix(t *testing.T) {
+	j := Job{
+		MixinName: "build",
+		Async:     true,
+		Vars:      Vars{"os": "plan9", "ext": ""},
+		Matrix: &Matrix{Vars: []MatrixVar{
+			{Name: "os", Values: []string{"linux", "windows"}},
+		}},
+	}
+
+	got := j.ExpandMatrix()
+	require.Len(t, got, 2)
+	for i, os := range []string{"linux", "windows"} {
+		assert.Nil(t, got[i].Matrix)
+		assert.True(t, got[i].Async)
+		assert.Equal(t, Vars{"os": os, "ext": ""}, got[i].Vars)
+		assert.Equal(t, "build (os: "+os+")", got[i].FormatDescription())
+	}
+
+	assert.Equal(t, Vars{"os": "plan9", "ext": ""}, j.Vars, "source job should not be modified")
+	assert.Equal(t, []Job{{ActionName: "foo"}}, Job{ActionName: "foo"}.ExpandMatrix())
+}
@@ -8,10 +8,10 @@ type Mixin []Job
 
 // ToTask creates a new task from mixin with variables for override
 func (m Mixin) ToTask(parentVars Vars) (t Task) {
-	t = make(Task, 0, len(m))
+	t.Jobs = make([]Job, 0, len(m))
 	for _, j := range m {
 		j.Vars = j.Vars.Append(parentVars)
-		t = append(t, j)
+		t.Jobs = append(t.Jobs, j)
 	}
 
 	return t
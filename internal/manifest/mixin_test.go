@@ -23,10 +23,10 @@ func TestMixin_ToTask(t *testing.T) {
 		"bar": "foo",
 	}
 
-	expected := Task{
+	expected := Task{Jobs: []Job{
 		{ActionName: "build", Async: true, Vars: vars},
 		{ActionName: "shell", Async: true, Vars: vars},
-	}
+	}}
 
 	got := m.ToTask(vars)
 	assert.Equal(t, expected, got)
@@ -1,15 +1,188 @@
 package manifest
 
+import (
+	"fmt"
+	"strings"
+)
+
 // TaskSet is a set of tasks declared in a manifest file
 type TaskSet map[string]Task
 
+// CheckDependencies checks that all tasks required by specified task are defined
+// and dependency graph has no cycles.
+//
+// Sub-tasks called by "task" jobs are also checked for cycles.
+func (ts TaskSet) CheckDependencies(taskName string) error {
+	return ts.checkDependencies(taskName, nil, make(map[string]bool))
+}
+
+func (ts TaskSet) checkDependencies(taskName string, chain []string, checked map[string]bool) error {
+	for i, name := range chain {
+		if name == taskName {
+			cycle := append(append([]string{}, chain[i:]...), taskName)
+			return fmt.Errorf("task dependency cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	if checked[taskName] {
+		return nil
+	}
+
+	task, ok := ts[taskName]
+	if !ok {
+		if len(chain) == 0 {
+			return fmt.Errorf("task %q doesn't exists", taskName)
+		}
+
+		return fmt.Errorf("task %q depends on undefined task %q", chain[len(chain)-1], taskName)
+	}
+
+	chain = append(chain, taskName)
+	for _, dep := range task.Depends {
+		if err := ts.checkDependencies(dep, chain, checked); err != nil {
+			return err
+		}
+	}
+
+	// sub-tasks started by "task" jobs can't require a task which is already running.
+	// Undefined sub-tasks are reported by runner.
+	for _, j := range task.Jobs {
+		if _, ok := ts[j.TaskName]; !ok {
+			continue
+		}
+
+		if err := ts.checkDependencies(j.TaskName, chain, checked); err != nil {
+			return err
+		}
+	}
+
+	checked[taskName] = true
+	return nil
+}
+
+// AsyncFailurePolicy defines task behavior when one of async jobs failed
+type AsyncFailurePolicy string
+
+const (
+	// AsyncFailureWaitAll means that task waits until all async jobs complete
+	AsyncFailureWaitAll AsyncFailurePolicy = "wait-all"
+
+	// AsyncFailureCancelSiblings means that other async jobs of the task
+	// will be cancelled after the first failure
+	AsyncFailureCancelSiblings AsyncFailurePolicy = "cancel-siblings"
+)
+
+// UnmarshalYAML implements yaml.InterfaceUnmarshaler
+func (p *AsyncFailurePolicy) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+
+	switch v := AsyncFailurePolicy(str); v {
+	case "", AsyncFailureWaitAll, AsyncFailureCancelSiblings:
+		*p = v
+		return nil
+	default:
+		return fmt.Errorf("unsupported async failure policy %q (expected %s or %s)",
+			str, AsyncFailureWaitAll, AsyncFailureCancelSiblings)
+	}
+}
+
 // Task is a group of jobs
-type Task []Job
+//
+// Task can be declared as a plain list of jobs or as an object
+// with a list of jobs and task options:
+//
+//	build:
+//	  depends: [lint, generate]
+//	  onAsyncFailure: cancel-siblings
+//	  maxParallel: 2
+//	  jobs:
+//	    - action: build
+type Task struct {
+	// Depends is a list of tasks that should be completed before the task.
+	Depends []string `yaml:"depends,omitempty"`
+
+	// OnAsyncFailure defines what to do with other async jobs
+	// when one of them failed.
+	//
+	// Task waits for all async jobs by default.
+	OnAsyncFailure AsyncFailurePolicy `yaml:"onAsyncFailure,omitempty"`
+
+	// MaxParallel limits count of async jobs of the task running at the same time.
+	//
+	// Zero means no limit.
+	MaxParallel int `yaml:"maxParallel,omitempty"`
+
+	// Jobs is a list of task steps.
+	Jobs []Job `yaml:"jobs,omitempty"`
+}
+
+// taskDeclaration is an object form of task declaration.
+//
+// Used to prevent recursive call of UnmarshalYAML.
+type taskDeclaration Task
+
+// UnmarshalYAML implements yaml.InterfaceUnmarshaler
+func (t *Task) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var jobs []Job
+	if err := unmarshal(&jobs); err == nil {
+		*t = Task{Jobs: jobs}
+		return nil
+	}
+
+	var decl taskDeclaration
+	if err := unmarshal(&decl); err != nil {
+		return err
+	}
+
+	*t = Task(decl)
+	return nil
+}
+
+// MarshalYAML implements yaml.InterfaceMarshaler
+func (t Task) MarshalYAML() (interface{}, error) {
+	if !t.HasDependencies() && t.OnAsyncFailure == "" && t.MaxParallel == 0 {
+		// Keep short form if task has no options
+		return t.Jobs, nil
+	}
+
+	return taskDeclaration(t), nil
+}
+
+// HasDependencies checks if task requires another tasks to be completed
+func (t Task) HasDependencies() bool {
+	return len(t.Depends) > 0
+}
+
+// ExpandMatrix returns a task copy with jobs expanded for each matrix combination
+func (t Task) ExpandMatrix() Task {
+	hasMatrix := false
+	for _, j := range t.Jobs {
+		if j.Matrix != nil {
+			hasMatrix = true
+			break
+		}
+	}
+
+	if !hasMatrix {
+		return t
+	}
+
+	out := t
+	out.Jobs = make([]Job, 0, len(t.Jobs))
+	for _, j := range t.Jobs {
+		out.Jobs = append(out.Jobs, j.ExpandMatrix()...)
+	}
+
+	return out
+}
 
 // AsyncJobsCount returns count of async jobs in the task
 func (t Task) AsyncJobsCount() (count int) {
-	for i := range t {
-		if t[i].Async {
+	for i := range t.Jobs {
+		if t.Jobs[i].Async {
 			count++
 		}
 	}
@@ -19,10 +192,11 @@ func (t Task) AsyncJobsCount() (count int) {
 
 // Clone creates a new task copy with specified variables
 func (t Task) Clone(vars Vars) Task {
-	out := make(Task, len(t))
-	for i, j := range t {
+	out := t
+	out.Jobs = make([]Job, len(t.Jobs))
+	for i, j := range t.Jobs {
 		j.Vars = j.Vars.Append(vars)
-		out[i] = j
+		out.Jobs[i] = j
 	}
 
 	return out
@@ -3,15 +3,17 @@ package manifest
 import (
 	"testing"
 
+	"github.com/goccy/go-yaml"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTask_AsyncJobsCount(t *testing.T) {
-	tsk := Task{
+	tsk := Task{Jobs: []Job{
 		{Async: true},
 		{Async: false},
 		{Async: true},
-	}
+	}}
 
 	got := tsk.AsyncJobsCount()
 	assert.Equal(t, 2, got)
@@ -19,15 +21,156 @@ func TestTask_AsyncJobsCount(t *testing.T) {
 
 func TestTask_Clone(t *testing.T) {
 	expected := Task{
-		{
-			Description: "foo",
-			Vars: Vars{
-				"v1": "foo",
-				"v2": "bar",
+		Depends: []string{"bar"},
+		Jobs: []Job{
+			{
+				Description: "foo",
+				Vars: Vars{
+					"v1": "foo",
+					"v2": "bar",
+				},
 			},
 		},
 	}
-	origin := Task{{Description: "foo", Vars: Vars{"v1": "foo"}}}
+	origin := Task{Depends: []string{"bar"}, Jobs: []Job{{Description: "foo", Vars: Vars{"v1": "foo"}}}}
 	got := origin.Clone(Vars{"v2": "bar"})
 	assert.Equal(t, expected, got)
 }
+
+func TestTaskSet_CheckDependencies(t *testing.T) {
+	cases := map[string]struct {
+		task string
+		err  string
+		ts   TaskSet
+	}{
+		"no dependencies": {
+			task: "foo",
+			ts:   TaskSet{"foo": Task{}},
+		},
+		"shared dependencies": {
+			task: "release",
+			ts: TaskSet{
+				"release":  Task{Depends: []string{"build", "lint"}},
+				"build":    Task{Depends: []string{"generate"}},
+				"lint":     Task{Depends: []string{"generate"}},
+				"generate": Task{},
+			},
+		},
+		"undefined task": {
+			task: "foo",
+			err:  `task "foo" doesn't exists`,
+			ts:   TaskSet{},
+		},
+		"undefined dependency": {
+			task: "foo",
+			err:  `task "bar" depends on undefined task "baz"`,
+			ts: TaskSet{
+				"foo": Task{Depends: []string{"bar"}},
+				"bar": Task{Depends: []string{"baz"}},
+			},
+		},
+		"self dependency": {
+			task: "foo",
+			err:  "task dependency cycle: foo -> foo",
+			ts: TaskSet{
+				"foo": Task{Depends: []string{"foo"}},
+			},
+		},
+		"dependency cycle": {
+			task: "foo",
+			err:  "task dependency cycle: bar -> baz -> bar",
+			ts: TaskSet{
+				"foo": Task{Depends: []string{"bar"}},
+				"bar": Task{Depends: []string{"baz"}},
+				"baz": Task{Depends: []string{"bar"}},
+			},
+		},
+		"sub-task cycle": {
+			task: "release",
+			err:  "task dependency cycle: release -> build -> release",
+			ts: TaskSet{
+				"release": Task{Depends: []string{"build"}},
+				"build":   Task{Jobs: []Job{{TaskName: "release"}}},
+			},
+		},
+		"sub-task without cycle": {
+			task: "release",
+			ts: TaskSet{
+				"release": Task{Depends: []string{"build"}, Jobs: []Job{{TaskName: "build"}, {TaskName: "missing"}}},
+				"build":   Task{},
+			},
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			err := c.ts.CheckDependencies(c.task)
+			if c.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.EqualError(t, err, c.err)
+		})
+	}
+}
+
+func TestTask_UnmarshalYAML(t *testing.T) {
+	cases := map[string]struct {
+		src  string
+		err  string
+		want Task
+	}{
+		"list of jobs": {
+			src: "- action: build\n",
+			want: Task{
+				Jobs: []Job{{ActionName: "build"}},
+			},
+		},
+		"task declaration": {
+			src: "depends:\n- lint\njobs:\n- action: build\n",
+			want: Task{
+				Depends: []string{"lint"},
+				Jobs:    []Job{{ActionName: "build"}},
+			},
+		},
+		"async failure policy": {
+			src: "onAsyncFailure: cancel-siblings\njobs:\n- action: build\n",
+			want: Task{
+				OnAsyncFailure: AsyncFailureCancelSiblings,
+				Jobs:           []Job{{ActionName: "build"}},
+			},
+		},
+		"max parallel": {
+			src: "maxParallel: 2\njobs:\n- action: build\n",
+			want: Task{
+				MaxParallel: 2,
+				Jobs:        []Job{{ActionName: "build"}},
+			},
+		},
+		"invalid async failure policy": {
+			src: "onAsyncFailure: foo\njobs:\n- action: build\n",
+			err: `unsupported async failure policy "foo"`,
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			var got Task
+			err := yaml.Unmarshal([]byte(c.src), &got)
+			if c.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, c.want, got)
+
+			// Check if task can be marshaled back
+			data, err := yaml.Marshal(got)
+			require.NoError(t, err)
+			require.Equal(t, c.src, string(data))
+		})
+	}
+}
@@ -1,8 +1,57 @@
 package manifest
 
+import (
+	"encoding/json"
+
+	"github.com/goccy/go-yaml"
+)
+
 // Vars is a set of declared variables
 type Vars map[string]string
 
+// UnmarshalYAML decodes variables from YAML.
+//
+// Lists and maps are stored as JSON and decoded back by expr.TypedValue,
+// so "${...}" expressions and job conditions can access them by index or key.
+func (v *Vars) UnmarshalYAML(unmarshal func(any) error) error {
+	var raw map[string]varValue
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+
+	out := make(Vars, len(raw))
+	for k, val := range raw {
+		out[k] = string(val)
+	}
+
+	*v = out
+	return nil
+}
+
+// varValue is variable value which might be a scalar, a list or a map
+type varValue string
+
+func (v *varValue) UnmarshalYAML(data []byte) error {
+	var str string
+	if err := yaml.Unmarshal(data, &str); err == nil {
+		*v = varValue(str)
+		return nil
+	}
+
+	var val any
+	if err := yaml.Unmarshal(data, &val); err != nil {
+		return err
+	}
+
+	out, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+
+	*v = varValue(out)
+	return nil
+}
+
 // Append appends variables from vars list
 func (v Vars) Append(newVars Vars) (out Vars) {
 	if v == nil {
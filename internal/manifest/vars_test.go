@@ -0,0 +1,21 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVars_UnmarshalYAML(t *testing.T) {
+	var m Manifest
+	src := "vars:\n  str: foo\n  num: 1\n  empty:\n  list: [a, 1]\n  map:\n    a: [b]\n"
+	require.NoError(t, yaml.Unmarshal([]byte(src), &m))
+	require.Equal(t, Vars{
+		"str":   "foo",
+		"num":   "1",
+		"empty": "",
+		"list":  `["a",1]`,
+		"map":   `{"a":["b"]}`,
+	}, m.Vars)
+}
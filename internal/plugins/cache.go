@@ -0,0 +1,113 @@
+package plugins
+
+import (
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-gilbert/gilbert/internal/plugins/support"
+	"github.com/go-gilbert/gilbert/internal/storage"
+	fsutil "github.com/go-gilbert/gilbert/internal/support/fs"
+)
+
+// tmpFileExt is extension of partially downloaded files
+const tmpFileExt = ".tmp"
+
+// CachedPlugin is a plugin stored in plugins storage
+type CachedPlugin struct {
+	support.Artifact
+
+	// Name is plugin name derived from plugin import URL
+	Name string
+
+	// Size is plugin file size
+	Size int64
+}
+
+// Matches checks if plugin has specified name or import URL
+func (p CachedPlugin) Matches(nameOrURL string) bool {
+	return p.Name == nameOrURL || (p.URL != "" && p.URL == nameOrURL)
+}
+
+// Remove deletes plugin from plugins storage
+func (p CachedPlugin) Remove() error {
+	if err := p.Artifact.Remove(); err != nil {
+		return err
+	}
+
+	// Plugin directory is removed only if it's empty
+	_ = os.Remove(filepath.Dir(p.Path))
+	return nil
+}
+
+// ListCached returns plugins stored in plugins storage
+func ListCached() ([]CachedPlugin, error) {
+	dir, err := storage.Path(storage.Plugins)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := fsutil.Exists(dir)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	var out []CachedPlugin
+	err = filepath.WalkDir(dir, func(fileName string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if d.Name() == support.WasmCacheDir {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if support.IsMetadataFile(fileName) || strings.HasSuffix(fileName, tmpFileExt) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		a, err := support.ReadArtifact(fileName)
+		if err != nil {
+			return err
+		}
+
+		if a.LastUsed.IsZero() {
+			a.LastUsed = info.ModTime()
+		}
+
+		out = append(out, CachedPlugin{Artifact: *a, Name: pluginName(a), Size: info.Size()})
+		return nil
+	})
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Name == out[j].Name {
+			return out[i].Path < out[j].Path
+		}
+
+		return out[i].Name < out[j].Name
+	})
+	return out, err
+}
+
+// pluginName returns plugin name from import URL or plugin file name
+func pluginName(a *support.Artifact) string {
+	name := filepath.Base(a.Path)
+	if uri, err := url.Parse(a.URL); err == nil && a.URL != "" {
+		name = path.Base(uri.Path)
+	}
+
+	return strings.TrimSuffix(name, support.WasmExtension)
+}
@@ -0,0 +1,70 @@
+package plugins
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-gilbert/gilbert/internal/log"
+	"github.com/go-gilbert/gilbert/internal/plugins/lockfile"
+	"github.com/go-gilbert/gilbert/internal/plugins/support"
+	"github.com/go-gilbert/gilbert/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListCached(t *testing.T) {
+	log.UseTestLogger(t)
+	home := t.TempDir()
+	t.Setenv(storage.StoreVarName, home)
+
+	cached, err := ListCached()
+	require.NoError(t, err)
+	assert.Empty(t, cached, "missing storage should be ignored")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("plugin"))
+	}))
+	defer srv.Close()
+
+	pluginURL := srv.URL + "/plugins/foo.wasm"
+	_, err = Resolve(context.Background(), pluginURL, lockfile.New(""), LockInstall)
+	require.NoError(t, err)
+
+	// plugin without metadata and files which should be ignored
+	dir := filepath.Join(home, "plugins", "github", "abc")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bar_linux-amd64"), []byte("bar"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bar_linux-amd64.tmp"), []byte("bar"), 0755))
+	wasmCache := filepath.Join(home, "plugins", support.WasmCacheDir)
+	require.NoError(t, os.MkdirAll(wasmCache, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(wasmCache, "module"), []byte("bar"), 0644))
+
+	cached, err = ListCached()
+	require.NoError(t, err)
+	require.Len(t, cached, 2)
+
+	assert.Equal(t, "bar_linux-amd64", cached[0].Name)
+	assert.Equal(t, int64(3), cached[0].Size)
+	assert.False(t, cached[0].LastUsed.IsZero())
+
+	assert.Equal(t, "foo", cached[1].Name)
+	assert.Equal(t, pluginURL, cached[1].URL)
+	assert.Equal(t, pluginURL, cached[1].Source)
+	assert.Equal(t, int64(6), cached[1].Size)
+	assert.True(t, cached[1].Matches("foo"))
+	assert.True(t, cached[1].Matches(pluginURL))
+	assert.False(t, cached[1].Matches("bar"))
+
+	require.NoError(t, cached[1].Remove())
+	_, err = os.Stat(filepath.Dir(cached[1].Path))
+	assert.True(t, os.IsNotExist(err), "empty plugin directory should be removed")
+
+	cached, err = ListCached()
+	require.NoError(t, err)
+	require.Len(t, cached, 1)
+	assert.Equal(t, "bar_linux-amd64", cached[0].Name)
+}
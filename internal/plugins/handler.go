@@ -8,10 +8,13 @@ import (
 	"github.com/go-gilbert/gilbert/internal/plugins/sources/github"
 	"github.com/go-gilbert/gilbert/internal/plugins/sources/gopkg"
 	"github.com/go-gilbert/gilbert/internal/plugins/sources/http"
+	"github.com/go-gilbert/gilbert/internal/plugins/support"
 )
 
-// SourceProvider provides and installs plugin from source
-type SourceProvider func(context.Context, *url.URL) (string, error)
+// SourceProvider provides and installs plugin from source.
+//
+// Cached plugin should be fetched again if refresh is true.
+type SourceProvider func(ctx context.Context, uri *url.URL, refresh bool) (*support.Artifact, error)
 
 var importHandlers = map[string]SourceProvider{
 	"file":               getLocalPlugin,
@@ -21,6 +24,16 @@ var importHandlers = map[string]SourceProvider{
 	gopkg.ProviderName:   gopkg.GetPlugin,
 }
 
-func getLocalPlugin(_ context.Context, uri *url.URL) (string, error) {
-	return filepath.Join(uri.Host, uri.Path), nil
+// remoteProviders is a list of remote plugin sources.
+//
+// Plugins from these sources are cached in plugins storage and recorded in the lock file.
+var remoteProviders = map[string]bool{
+	github.ProviderName:  true,
+	http.AltProviderName: true,
+	http.ProviderName:    true,
+}
+
+func getLocalPlugin(_ context.Context, uri *url.URL, _ bool) (*support.Artifact, error) {
+	pluginPath := filepath.Join(uri.Host, uri.Path)
+	return &support.Artifact{Path: pluginPath, Source: pluginPath}, nil
 }
@@ -0,0 +1,74 @@
+package loader
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-gilbert/gilbert/internal/manifest"
+	"github.com/go-gilbert/gilbert/internal/plugins/rpc"
+	"github.com/go-gilbert/gilbert/internal/runner"
+	"github.com/go-gilbert/gilbert/internal/runner/job"
+	"github.com/go-gilbert/gilbert/internal/scope"
+)
+
+// actionHandler calls plugin action using plugin protocol
+type actionHandler struct {
+	plugin *Plugin
+	action string
+	scope  *scope.Scope
+	params manifest.ActionParams
+
+	mtx   sync.Mutex
+	jobID string
+}
+
+func (p *Plugin) newActionHandler(action string) runner.HandlerFactory {
+	return func(s *scope.Scope, params manifest.ActionParams) (runner.ActionHandler, error) {
+		return &actionHandler{plugin: p, action: action, scope: s, params: params}, nil
+	}
+}
+
+// Call implements runner.ActionHandler
+func (a *actionHandler) Call(ctx *job.RunContext, _ *runner.TaskRunner) error {
+	params, err := a.scope.ExpandParams(a.params)
+	if err != nil {
+		return err
+	}
+
+	vars, err := a.scope.ResolveVars()
+	if err != nil {
+		return err
+	}
+
+	jobID := a.plugin.newJobID()
+	a.mtx.Lock()
+	a.jobID = jobID
+	a.mtx.Unlock()
+
+	a.plugin.setLogger(jobID, ctx.Log())
+	defer a.plugin.setLogger(jobID, nil)
+
+	// Action is cancelled using "cancel" request, so job context is not used here
+	return a.plugin.conn.Call(context.Background(), rpc.MethodCall, rpc.CallParams{
+		JobID:   jobID,
+		Action:  a.action,
+		Params:  params,
+		Vars:    vars,
+		WorkDir: a.plugin.workDir,
+	}, nil)
+}
+
+// Cancel implements runner.ActionHandler
+func (a *actionHandler) Cancel(_ *job.RunContext) error {
+	a.mtx.Lock()
+	jobID := a.jobID
+	a.mtx.Unlock()
+	if jobID == "" {
+		// Action was not started
+		return nil
+	}
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancelFn()
+	return a.plugin.conn.Call(ctx, rpc.MethodCancel, rpc.CancelParams{JobID: jobID}, nil)
+}
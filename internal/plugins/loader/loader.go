@@ -1,16 +1,232 @@
-//go:build linux || darwin
-// +build linux darwin
-
 package loader
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/go-gilbert/gilbert/internal/log"
+	"github.com/go-gilbert/gilbert/internal/plugins/rpc"
+	"github.com/go-gilbert/gilbert/internal/plugins/support"
 	"github.com/go-gilbert/gilbert/internal/runner"
 )
 
-// LoadPlugin loads plugin from provided source
-func LoadPlugin(libPath string) (pluginName string, pluginActions runner.ActionHandlers, err error) {
-	// TODO: reimplement
-	return "", nil, errors.New("plugin feature not implemented")
+const (
+	initTimeout     = 10 * time.Second
+	shutdownTimeout = 5 * time.Second
+)
+
+// Plugin is a running plugin instance
+type Plugin struct {
+	name    string
+	actions []string
+	workDir string
+	conn    *rpc.Conn
+	exited  chan struct{}
+	stop    func() error
+	lastJob uint64
+
+	mtx     sync.Mutex
+	loggers map[string]log.Logger
+}
+
+func newPlugin(workDir string) *Plugin {
+	return &Plugin{
+		workDir: workDir,
+		exited:  make(chan struct{}),
+		loggers: make(map[string]log.Logger),
+	}
+}
+
+// LoadPlugin starts plugin and performs protocol handshake.
+//
+// Plugin might be a native executable or WebAssembly module (*.wasm).
+// Project directory is used as plugin working directory.
+func LoadPlugin(ctx context.Context, pluginPath, projectDir string) (*Plugin, error) {
+	start := startProcess
+	if support.IsWasmModule(pluginPath) {
+		start = startWasmModule
+	}
+
+	p, err := start(ctx, pluginPath, projectDir)
+	if err != nil {
+		return nil, err
+	}
+
+	initCtx, cancelFn := context.WithTimeout(ctx, initTimeout)
+	defer cancelFn()
+
+	var rsp rpc.InitializeResult
+	err = p.conn.Call(initCtx, rpc.MethodInitialize, rpc.InitializeParams{ProtocolVersion: rpc.ProtocolVersion}, &rsp)
+	if err == nil {
+		err = rpc.CheckVersion(rsp.ProtocolVersion)
+	}
+
+	if err != nil {
+		_ = p.kill()
+		return nil, fmt.Errorf("plugin handshake failed: %w", err)
+	}
+
+	p.name = strings.TrimSpace(rsp.Name)
+	p.actions = rsp.Actions
+	log.Default.Debugf("loader: plugin %q (%s) provides actions: %s",
+		p.name, pluginPath, strings.Join(p.actions, ", "))
+	return p, nil
+}
+
+// startProcess starts plugin executable
+func startProcess(_ context.Context, execPath, workDir string) (*Plugin, error) {
+	cmd := exec.Command(execPath)
+	cmd.Dir = workDir
+	cmd.Stderr = log.Default.ErrorWriter()
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	// Pipe is created manually because cmd.Wait() closes stdout pipe
+	// returned by cmd.StdoutPipe() and unread messages may be lost.
+	stdout, stdoutW, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+
+	cmd.Stdout = stdoutW
+	err = cmd.Start()
+	_ = stdoutW.Close()
+	if err != nil {
+		_ = stdout.Close()
+		return nil, fmt.Errorf("failed to start plugin process: %w", err)
+	}
+
+	p := newPlugin(workDir)
+	p.stop = cmd.Process.Kill
+	p.connect(stdout, stdin)
+	go func() {
+		_ = cmd.Wait()
+		close(p.exited)
+	}()
+
+	return p, nil
+}
+
+// connect establishes plugin protocol connection over plugin's stdout and stdin
+func (p *Plugin) connect(r io.ReadCloser, w io.Writer) {
+	p.conn = rpc.NewConn(r, w, p.handle)
+	go func() {
+		<-p.conn.Done()
+		_ = r.Close()
+	}()
+}
+
+// Name returns plugin name
+func (p *Plugin) Name() string {
+	return p.name
+}
+
+// Actions returns action handlers provided by plugin.
+//
+// Key is an action name without plugin prefix.
+func (p *Plugin) Actions() runner.ActionHandlers {
+	out := make(runner.ActionHandlers, len(p.actions))
+	for _, name := range p.actions {
+		out[name] = p.newActionHandler(name)
+	}
+
+	return out
+}
+
+// Close asks plugin to exit and waits for plugin exit.
+//
+// Plugin is killed if it didn't exit in time.
+func (p *Plugin) Close() error {
+	ctx, cancelFn := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancelFn()
+
+	// Plugin may exit before sending a response
+	err := p.conn.Call(ctx, rpc.MethodShutdown, nil, nil)
+	if err != nil && !errors.Is(err, rpc.ErrClosed) {
+		log.Default.Debugf("loader: plugin %q shutdown request failed: %s", p.name, err)
+	}
+
+	select {
+	case <-p.exited:
+		return nil
+	case <-ctx.Done():
+		log.Default.Warnf("Plugin %q didn't exit in time and will be killed", p.name)
+		return p.kill()
+	}
+}
+
+func (p *Plugin) kill() error {
+	if err := p.stop(); err != nil {
+		return err
+	}
+
+	<-p.exited
+	return nil
+}
+
+func (p *Plugin) newJobID() string {
+	return strconv.FormatUint(atomic.AddUint64(&p.lastJob, 1), 10)
+}
+
+func (p *Plugin) setLogger(jobID string, l log.Logger) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if l == nil {
+		delete(p.loggers, jobID)
+		return
+	}
+
+	p.loggers[jobID] = l
+}
+
+// handle handles notifications from plugin
+func (p *Plugin) handle(_ context.Context, _ *rpc.Conn, method string, params json.RawMessage) (interface{}, error) {
+	if method != rpc.MethodLog {
+		return nil, &rpc.Error{Code: rpc.CodeMethodNotFound, Message: "method not found: " + method}
+	}
+
+	var msg rpc.LogParams
+	if err := json.Unmarshal(params, &msg); err != nil {
+		return nil, &rpc.Error{Code: rpc.CodeInvalidParams, Message: err.Error()}
+	}
+
+	p.mtx.Lock()
+	l, ok := p.loggers[msg.JobID]
+	p.mtx.Unlock()
+	if !ok {
+		l = log.Default
+	}
+
+	writeLog(l, msg.Level, msg.Message)
+	return nil, nil
+}
+
+func writeLog(l log.Logger, level rpc.Level, msg string) {
+	switch level {
+	case rpc.LevelDebug:
+		l.Debug(msg)
+	case rpc.LevelInfo:
+		l.Info(msg)
+	case rpc.LevelSuccess:
+		l.Success(msg)
+	case rpc.LevelWarn:
+		l.Warn(msg)
+	case rpc.LevelError:
+		l.Error(msg)
+	default:
+		l.Log(msg)
+	}
 }
@@ -0,0 +1,152 @@
+package loader
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-gilbert/gilbert/internal/log"
+	"github.com/go-gilbert/gilbert/internal/manifest"
+	"github.com/go-gilbert/gilbert/internal/manifest/expr"
+	"github.com/go-gilbert/gilbert/internal/plugins/support"
+	"github.com/go-gilbert/gilbert/internal/runner/job"
+	"github.com/go-gilbert/gilbert/internal/scope"
+	"github.com/go-gilbert/gilbert/internal/storage"
+	"github.com/go-gilbert/gilbert/internal/support/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildTestPlugin builds test plugin from testdata for specified platform
+func buildTestPlugin(t *testing.T, fileName string, env ...string) string {
+	if testing.Short() {
+		t.Skip("test plugin build is skipped in short mode")
+	}
+
+	out := filepath.Join(t.TempDir(), fileName)
+	cmd := exec.Command("go", "build", "-o", out, "./testdata/plugin")
+	cmd.Env = append(os.Environ(), env...)
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, "failed to build test plugin:\n%s", output)
+	return out
+}
+
+func TestLoadPlugin(t *testing.T) {
+	t.Setenv(storage.StoreVarName, t.TempDir())
+	cases := map[string]struct {
+		fileName string
+		env      []string
+		workDir  string
+	}{
+		"native executable": {
+			fileName: support.AddPluginExtension("plugin"),
+		},
+		"WebAssembly module": {
+			fileName: "plugin" + support.WasmExtension,
+			env:      []string{"GOOS=wasip1", "GOARCH=wasm"},
+			workDir:  wasmProjectDir,
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			log.UseTestLogger(t)
+			l := &test.Log{T: t}
+
+			pluginPath := buildTestPlugin(t, c.fileName, c.env...)
+			projectDir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(projectDir, "file.txt"), []byte("file contents"), 0644))
+
+			p, err := LoadPlugin(context.Background(), pluginPath, projectDir)
+			require.NoError(t, err)
+			defer func() {
+				assert.NoError(t, p.Close())
+			}()
+
+			if c.workDir == "" {
+				c.workDir = projectDir
+			}
+
+			assert.Equal(t, "test", p.Name())
+			assert.Equal(t, c.workDir, p.workDir)
+			handlers := p.Actions()
+			require.Len(t, handlers, 4)
+
+			s := scope.CreateScope(expr.SpecV2Parser{}, projectDir, manifest.Vars{"name": "${who}"}).
+				AppendGlobals(manifest.Vars{"who": "world"})
+			call := func(action string, params manifest.ActionParams) error {
+				h, err := handlers[action](s, params)
+				require.NoError(t, err)
+				return h.Call(job.NewRunContext(context.Background(), nil, l), nil)
+			}
+
+			require.NoError(t, call("echo", manifest.ActionParams{"message": "hello"}))
+			l.AssertMessage("hello world")
+
+			require.NoError(t, call("read", manifest.ActionParams{"file": "file.txt"}))
+			l.AssertMessage("file contents")
+
+			assert.EqualError(t, call("fail", nil), "action failed")
+
+			h, err := handlers["wait"](s, nil)
+			require.NoError(t, err)
+			ctx := job.NewRunContext(context.Background(), nil, l)
+			require.NoError(t, h.Cancel(ctx), "cancel before call should be ignored")
+
+			result := make(chan error, 1)
+			go func() {
+				result <- h.Call(ctx, nil)
+			}()
+
+			time.Sleep(100 * time.Millisecond)
+			require.NoError(t, h.Cancel(ctx))
+			select {
+			case err := <-result:
+				require.NoError(t, err)
+			case <-time.After(5 * time.Second):
+				t.Fatal("action was not cancelled")
+			}
+			l.AssertMessage("cancelled")
+		})
+	}
+}
+
+func TestLoadPlugin_Error(t *testing.T) {
+	log.UseTestLogger(t)
+	cases := map[string]struct {
+		fileName string
+		data     []byte
+		err      string
+	}{
+		"missing executable": {
+			fileName: "missing",
+			err:      "failed to start plugin process",
+		},
+		"missing module": {
+			fileName: "missing.wasm",
+			err:      "failed to read WebAssembly module",
+		},
+		"invalid module": {
+			fileName: "invalid.wasm",
+			data:     []byte("foo"),
+			err:      "failed to compile WebAssembly module",
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			dir := t.TempDir()
+			pluginPath := filepath.Join(dir, c.fileName)
+			if c.data != nil {
+				require.NoError(t, os.WriteFile(pluginPath, c.data, 0644))
+			}
+
+			_, err := LoadPlugin(context.Background(), pluginPath, dir)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), c.err)
+		})
+	}
+}
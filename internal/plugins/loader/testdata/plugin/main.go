@@ -0,0 +1,48 @@
+// Command plugin is a test plugin used by loader tests.
+//
+// Plugin is built as a native executable and as a WebAssembly module.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-gilbert/gilbert/internal/plugins/rpc"
+)
+
+func main() {
+	p := &rpc.Plugin{
+		Name: "test",
+		Actions: map[string]rpc.ActionFunc{
+			"echo": func(_ context.Context, call *rpc.ActionCall) error {
+				call.Logf(rpc.LevelInfo, "%s %s", call.Params["message"], call.Vars["name"])
+				return nil
+			},
+			"read": func(_ context.Context, call *rpc.ActionCall) error {
+				data, err := os.ReadFile(filepath.Join(call.WorkDir, fmt.Sprint(call.Params["file"])))
+				if err != nil {
+					return err
+				}
+
+				call.Logf(rpc.LevelInfo, "%s", data)
+				return nil
+			},
+			"fail": func(context.Context, *rpc.ActionCall) error {
+				return errors.New("action failed")
+			},
+			"wait": func(ctx context.Context, call *rpc.ActionCall) error {
+				<-ctx.Done()
+				call.Logf(rpc.LevelWarn, "cancelled")
+				return nil
+			},
+		},
+	}
+
+	if err := p.Serve(os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,120 @@
+package loader
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-gilbert/gilbert/internal/log"
+	"github.com/go-gilbert/gilbert/internal/plugins/support"
+	"github.com/go-gilbert/gilbert/internal/storage"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"github.com/tetratelabs/wazero/sys"
+)
+
+const (
+	// wasmProjectDir is project directory mount point inside WebAssembly plugin.
+	//
+	// Plugin has no access to files outside of project directory.
+	wasmProjectDir = "/project"
+)
+
+// startWasmModule runs WebAssembly plugin in embedded WASI runtime.
+//
+// Plugin protocol is served over module's stdin and stdout,
+// project directory is mounted as "/project".
+func startWasmModule(ctx context.Context, modulePath, projectDir string) (*Plugin, error) {
+	bin, err := os.ReadFile(modulePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read WebAssembly module: %w", err)
+	}
+
+	// Plugin lifetime is not bound to load context
+	runCtx, cancelFn := context.WithCancel(context.Background())
+	rt := wazero.NewRuntimeWithConfig(runCtx, newWasmRuntimeConfig())
+	cleanup := func() {
+		_ = rt.Close(runCtx)
+		cancelFn()
+	}
+
+	wasi_snapshot_preview1.MustInstantiate(runCtx, rt)
+	compiled, err := rt.CompileModule(ctx, bin)
+	if err != nil {
+		cleanup()
+		return nil, fmt.Errorf("failed to compile WebAssembly module: %w", err)
+	}
+
+	// OS pipe is used for stdin since WASI runtime can poll it.
+	// Otherwise reading stdin blocks all goroutines of the module.
+	stdin, stdinW, err := os.Pipe()
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+
+	stdout, stdoutW := io.Pipe()
+	name := strings.TrimSuffix(filepath.Base(modulePath), filepath.Ext(modulePath))
+	cfg := wazero.NewModuleConfig().
+		WithName(name).
+		WithArgs(name).
+		WithStdin(stdin).
+		WithStdout(stdoutW).
+		WithStderr(log.Default.ErrorWriter()).
+		WithFSConfig(wazero.NewFSConfig().WithDirMount(projectDir, wasmProjectDir)).
+		WithSysWalltime().
+		WithSysNanotime().
+		WithSysNanosleep().
+		WithRandSource(rand.Reader)
+
+	p := newPlugin(wasmProjectDir)
+	p.stop = func() error {
+		// Module might be blocked on reading stdin
+		_ = stdinW.Close()
+		cancelFn()
+		return nil
+	}
+
+	p.connect(stdout, stdinW)
+	go func() {
+		defer close(p.exited)
+		_, err := rt.InstantiateModule(runCtx, compiled, cfg)
+		if err != nil && !isCleanExit(err) {
+			log.Default.Debugf("loader: WebAssembly plugin %q exited: %s", name, err)
+		}
+
+		_ = stdoutW.Close()
+		_ = stdin.Close()
+		_ = stdinW.Close()
+		cleanup()
+	}()
+
+	return p, nil
+}
+
+func newWasmRuntimeConfig() wazero.RuntimeConfig {
+	cfg := wazero.NewRuntimeConfig().WithCloseOnContextDone(true)
+	dir, err := storage.Path(storage.Plugins, support.WasmCacheDir)
+	if err != nil {
+		return cfg
+	}
+
+	// Compilation cache speeds up subsequent plugin loads
+	cache, err := wazero.NewCompilationCacheWithDir(dir)
+	if err != nil {
+		log.Default.Debugf("loader: failed to init WebAssembly compilation cache: %s", err)
+		return cfg
+	}
+
+	return cfg.WithCompilationCache(cache)
+}
+
+func isCleanExit(err error) bool {
+	var exitErr *sys.ExitError
+	return errors.As(err, &exitErr) && exitErr.ExitCode() == 0
+}
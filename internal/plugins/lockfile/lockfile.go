@@ -0,0 +1,228 @@
+// Package lockfile implements plugins lock file.
+//
+// Lock file records resolved version, source and SHA-256 checksum of each remote plugin
+// declared in manifest. Checksum is recorded per platform since native plugins
+// are built for each OS and architecture separately.
+package lockfile
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/goccy/go-yaml"
+)
+
+// FileName is lock file name
+const FileName = "gilbert.lock"
+
+// ErrNotLocked is returned when plugin is missing in lock file
+var ErrNotLocked = errors.New("missing lock file entry")
+
+const header = "# This file is generated by Gilbert, do not edit it manually.\n" +
+	"# Use 'gilbert plugins update' to update locked plugins.\n"
+
+// Artifact is a locked plugin file for a specific platform
+type Artifact struct {
+	// Source is a location where plugin was fetched from
+	Source string `yaml:"source"`
+
+	// SHA256 is plugin file checksum
+	SHA256 string `yaml:"sha256"`
+}
+
+// Plugin is a locked plugin
+type Plugin struct {
+	// URL is plugin URL declared in manifest
+	URL string `yaml:"url"`
+
+	// Version is resolved plugin version
+	Version string `yaml:"version,omitempty"`
+
+	// Artifacts is a list of plugin files per platform
+	Artifacts map[string]Artifact `yaml:"artifacts"`
+}
+
+// File is a plugins lock file
+type File struct {
+	Plugins []*Plugin `yaml:"plugins"`
+
+	path    string
+	changed bool
+}
+
+// Path returns lock file path for a project directory
+func Path(projectDir string) string {
+	return filepath.Join(projectDir, FileName)
+}
+
+// New returns an empty lock file
+func New(path string) *File {
+	return &File{path: path}
+}
+
+// Load reads lock file.
+//
+// Empty lock file is returned if file doesn't exist.
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return New(path), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	f := New(path)
+	if err := yaml.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("failed to parse lock file %q: %s", path, err)
+	}
+
+	return f, nil
+}
+
+// Find returns locked plugin by URL
+func (f *File) Find(url string) *Plugin {
+	for _, p := range f.Plugins {
+		if p.URL == url {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// Changed reports whether lock file was changed since load
+func (f *File) Changed() bool {
+	return f.changed
+}
+
+// Verify checks plugin file checksum against lock file.
+//
+// Returns ErrNotLocked if plugin or its platform artifact is not present in lock file.
+func (f *File) Verify(url, version, platform string, a Artifact) error {
+	p := f.Find(url)
+	if p == nil {
+		return fmt.Errorf("%w: plugin %q is not locked", ErrNotLocked, url)
+	}
+
+	if p.Version != "" && version != "" && p.Version != version {
+		return fmt.Errorf("plugin %q version %s doesn't match locked version %s", url, version, p.Version)
+	}
+
+	locked, ok := p.Artifacts[platform]
+	if !ok {
+		return fmt.Errorf("%w: plugin %q has no locked checksum for %s", ErrNotLocked, url, platform)
+	}
+
+	if locked.SHA256 != a.SHA256 {
+		return fmt.Errorf("checksum mismatch for plugin %q (%s): expected %s, got %s",
+			url, platform, locked.SHA256, a.SHA256)
+	}
+
+	return nil
+}
+
+// Add adds plugin or platform artifact which is not present in lock file.
+//
+// Already locked artifacts are verified.
+func (f *File) Add(url, version, platform string, a Artifact) error {
+	err := f.Verify(url, version, platform, a)
+	if !errors.Is(err, ErrNotLocked) {
+		return err
+	}
+
+	p := f.Find(url)
+	if p == nil {
+		p = &Plugin{URL: url, Version: version}
+		f.Plugins = append(f.Plugins, p)
+	}
+
+	if p.Artifacts == nil {
+		p.Artifacts = make(map[string]Artifact)
+	}
+
+	p.Artifacts[platform] = a
+	f.changed = true
+	return nil
+}
+
+// Update replaces locked platform artifact of a plugin.
+//
+// Artifacts of other platforms are kept unless plugin version has changed.
+func (f *File) Update(url, version, platform string, a Artifact) {
+	p := f.Find(url)
+	if p == nil {
+		p = &Plugin{URL: url}
+		f.Plugins = append(f.Plugins, p)
+	}
+
+	if p.Artifacts == nil || p.Version != version {
+		p.Artifacts = make(map[string]Artifact)
+	}
+
+	p.Version = version
+	p.Artifacts[platform] = a
+	f.changed = true
+}
+
+// Retain removes plugins which URLs are not in the list
+func (f *File) Retain(urls []string) {
+	keep := make(map[string]struct{}, len(urls))
+	for _, url := range urls {
+		keep[url] = struct{}{}
+	}
+
+	out := f.Plugins[:0]
+	for _, p := range f.Plugins {
+		if _, ok := keep[p.URL]; ok {
+			out = append(out, p)
+			continue
+		}
+
+		f.changed = true
+	}
+
+	f.Plugins = out
+}
+
+// Save writes lock file
+func (f *File) Save() error {
+	sort.Slice(f.Plugins, func(i, j int) bool {
+		return f.Plugins[i].URL < f.Plugins[j].URL
+	})
+
+	data, err := yaml.Marshal(f)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(f.path, append([]byte(header), data...), 0644); err != nil {
+		return fmt.Errorf("failed to write lock file: %w", err)
+	}
+
+	f.changed = false
+	return nil
+}
+
+// Checksum returns SHA-256 checksum of a file
+func Checksum(fileName string) (string, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return "", err
+	}
+
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
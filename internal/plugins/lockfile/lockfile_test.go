@@ -0,0 +1,211 @@
+package lockfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFile_Verify(t *testing.T) {
+	const url = "github://github.com/foo/bar"
+	linux := Artifact{Source: "https://example.com/bar_linux-amd64", SHA256: "aaa"}
+	cases := map[string]struct {
+		locked   []*Plugin
+		version  string
+		platform string
+		artifact Artifact
+		changed  bool
+		err      string
+	}{
+		"report missing plugin": {
+			version:  "v1.0.0",
+			platform: "linux/amd64",
+			artifact: linux,
+			err:      `missing lock file entry: plugin "github://github.com/foo/bar" is not locked`,
+		},
+		"verify locked plugin": {
+			locked: []*Plugin{
+				{URL: url, Version: "v1.0.0", Artifacts: map[string]Artifact{"linux/amd64": linux}},
+			},
+			version:  "v1.0.0",
+			platform: "linux/amd64",
+			artifact: linux,
+		},
+		"report missing platform": {
+			locked: []*Plugin{
+				{URL: url, Version: "v1.0.0", Artifacts: map[string]Artifact{"linux/amd64": linux}},
+			},
+			version:  "v1.0.0",
+			platform: "wasm",
+			artifact: Artifact{Source: "https://example.com/bar.wasm", SHA256: "bbb"},
+			err:      `missing lock file entry: plugin "github://github.com/foo/bar" has no locked checksum for wasm`,
+		},
+		"report checksum mismatch": {
+			locked: []*Plugin{
+				{URL: url, Version: "v1.0.0", Artifacts: map[string]Artifact{"linux/amd64": linux}},
+			},
+			version:  "v1.0.0",
+			platform: "linux/amd64",
+			artifact: Artifact{Source: linux.Source, SHA256: "bbb"},
+			err:      `checksum mismatch for plugin "github://github.com/foo/bar" (linux/amd64): expected aaa, got bbb`,
+		},
+		"report version mismatch": {
+			locked: []*Plugin{
+				{URL: url, Version: "v1.0.0", Artifacts: map[string]Artifact{"linux/amd64": linux}},
+			},
+			version:  "v1.1.0",
+			platform: "linux/amd64",
+			artifact: linux,
+			err:      `plugin "github://github.com/foo/bar" version v1.1.0 doesn't match locked version v1.0.0`,
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			f := &File{Plugins: c.locked}
+			err := f.Verify(url, c.version, c.platform, c.artifact)
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, c.changed, f.Changed())
+			p := f.Find(url)
+			require.NotNil(t, p)
+			assert.Equal(t, c.artifact, p.Artifacts[c.platform])
+		})
+	}
+}
+
+func TestFile_Add(t *testing.T) {
+	const url = "github://github.com/foo/bar"
+	linux := Artifact{Source: "https://example.com/bar_linux-amd64", SHA256: "aaa"}
+	wasm := Artifact{Source: "https://example.com/bar.wasm", SHA256: "bbb"}
+	cases := map[string]struct {
+		locked   []*Plugin
+		platform string
+		artifact Artifact
+		expect   map[string]Artifact
+		changed  bool
+		err      string
+	}{
+		"add missing plugin": {
+			platform: "linux/amd64",
+			artifact: linux,
+			expect:   map[string]Artifact{"linux/amd64": linux},
+			changed:  true,
+		},
+		"add missing platform": {
+			locked: []*Plugin{
+				{URL: url, Version: "v1.0.0", Artifacts: map[string]Artifact{"linux/amd64": linux}},
+			},
+			platform: "wasm",
+			artifact: wasm,
+			expect:   map[string]Artifact{"linux/amd64": linux, "wasm": wasm},
+			changed:  true,
+		},
+		"verify locked platform": {
+			locked: []*Plugin{
+				{URL: url, Version: "v1.0.0", Artifacts: map[string]Artifact{"linux/amd64": linux}},
+			},
+			platform: "linux/amd64",
+			artifact: Artifact{Source: linux.Source, SHA256: "bbb"},
+			err:      `checksum mismatch for plugin "github://github.com/foo/bar" (linux/amd64): expected aaa, got bbb`,
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			f := &File{Plugins: c.locked}
+			err := f.Add(url, "v1.0.0", c.platform, c.artifact)
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, c.changed, f.Changed())
+			assert.Equal(t, &Plugin{URL: url, Version: "v1.0.0", Artifacts: c.expect}, f.Find(url))
+		})
+	}
+}
+
+func TestFile_Update(t *testing.T) {
+	const url = "github://github.com/foo/bar"
+	linux := Artifact{Source: "https://example.com/bar_linux-amd64", SHA256: "aaa"}
+	darwin := Artifact{Source: "https://example.com/bar_darwin-arm64", SHA256: "bbb"}
+	cases := map[string]struct {
+		version string
+		expect  map[string]Artifact
+	}{
+		"keep other platforms": {
+			version: "v1.0.0",
+			expect:  map[string]Artifact{"linux/amd64": {Source: linux.Source, SHA256: "ccc"}, "darwin/arm64": darwin},
+		},
+		"drop other platforms of previous version": {
+			version: "v1.1.0",
+			expect:  map[string]Artifact{"linux/amd64": {Source: linux.Source, SHA256: "ccc"}},
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			f := &File{Plugins: []*Plugin{
+				{URL: url, Version: "v1.0.0", Artifacts: map[string]Artifact{"linux/amd64": linux, "darwin/arm64": darwin}},
+			}}
+
+			f.Update(url, c.version, "linux/amd64", Artifact{Source: linux.Source, SHA256: "ccc"})
+			assert.True(t, f.Changed())
+			assert.Equal(t, &Plugin{URL: url, Version: c.version, Artifacts: c.expect}, f.Find(url))
+		})
+	}
+}
+
+func TestFile_Retain(t *testing.T) {
+	f := &File{Plugins: []*Plugin{{URL: "foo"}, {URL: "bar"}}}
+	f.Retain([]string{"foo", "bar"})
+	assert.False(t, f.Changed())
+
+	f.Retain([]string{"bar"})
+	assert.True(t, f.Changed())
+	assert.Equal(t, []*Plugin{{URL: "bar"}}, f.Plugins)
+}
+
+func TestFile_Save(t *testing.T) {
+	path := Path(t.TempDir())
+	f, err := Load(path)
+	require.NoError(t, err)
+	assert.Empty(t, f.Plugins)
+
+	require.NoError(t, f.Add("http://example.com/foo", "", "linux/amd64", Artifact{Source: "http://example.com/foo", SHA256: "aaa"}))
+	require.NoError(t, f.Add("github://github.com/bar/baz", "v1.0.0", "wasm", Artifact{Source: "https://example.com/baz.wasm", SHA256: "bbb"}))
+	require.NoError(t, f.Save())
+	assert.False(t, f.Changed())
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, []*Plugin{
+		{
+			URL:       "github://github.com/bar/baz",
+			Version:   "v1.0.0",
+			Artifacts: map[string]Artifact{"wasm": {Source: "https://example.com/baz.wasm", SHA256: "bbb"}},
+		},
+		{
+			URL:       "http://example.com/foo",
+			Artifacts: map[string]Artifact{"linux/amd64": {Source: "http://example.com/foo", SHA256: "aaa"}},
+		},
+	}, loaded.Plugins)
+}
+
+func TestChecksum(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "plugin")
+	require.NoError(t, os.WriteFile(fileName, []byte("foo"), 0644))
+
+	sum, err := Checksum(fileName)
+	require.NoError(t, err)
+	assert.Equal(t, "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae", sum)
+}
@@ -6,69 +6,191 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/go-gilbert/gilbert/internal/log"
+	"github.com/go-gilbert/gilbert/internal/manifest"
 	"github.com/go-gilbert/gilbert/internal/plugins/loader"
+	"github.com/go-gilbert/gilbert/internal/plugins/lockfile"
+	"github.com/go-gilbert/gilbert/internal/plugins/sources/github"
+	"github.com/go-gilbert/gilbert/internal/plugins/support"
 	"github.com/go-gilbert/gilbert/internal/runner"
+	"github.com/go-gilbert/gilbert/internal/scope"
 )
 
-func registerPluginAction(pName, hName string, handler runner.HandlerFactory) error {
-	// TODO: rewrite this from scratch
-	panic("NOT IMPLEMENTED")
-	//hName = strings.TrimSpace(hName)
-	//actionName := formatPluginActionName(pName, hName)
-	//if err := actions.HandleFunc(actionName, handler); err != nil {
-	//	return err
-	//}
-	//
-	//log.Default.Debugf("loader: registered action handler '%s'", actionName)
-	//return nil
+// formatPluginActionName returns action name with plugin prefix
+func formatPluginActionName(pName, hName string) string {
+	return pName + ":" + hName
 }
 
-// Import imports plugin from URL and loads it
-func Import(ctx context.Context, pluginURL string) (err error) {
-	defer func() {
-		if err != nil {
-			err = fmt.Errorf("failed to load plugin from '%s':\n%s", pluginURL, err)
-		}
-	}()
+func registerPluginAction(handlers *runner.HandlerSet, pName, hName string, handler runner.HandlerFactory) error {
+	hName = strings.TrimSpace(hName)
+	if hName == "" {
+		return errors.New("plugin action name should not be empty")
+	}
+
+	actionName := formatPluginActionName(pName, hName)
+	if err := handlers.HandleFunc(actionName, handler); err != nil {
+		return err
+	}
+
+	log.Default.Debugf("loader: registered action handler '%s'", actionName)
+	return nil
+}
+
+// LockMode defines how remote plugin is checked against lock file
+type LockMode int
+
+const (
+	// LockVerify requires plugin to be present in lock file
+	LockVerify LockMode = iota
+
+	// LockInstall adds plugins and platforms missing in lock file
+	LockInstall
+
+	// LockUpdate fetches plugin again and replaces its lock file entry
+	LockUpdate
+)
 
+// Resolve fetches plugin from URL and returns plugin file.
+//
+// Checksum of a remote plugin is checked using lock file according to lock mode.
+func Resolve(ctx context.Context, pluginURL string, lock *lockfile.File, mode LockMode) (*support.Artifact, error) {
 	uri, err := url.Parse(pluginURL)
 	if err != nil {
-		return fmt.Errorf("invalid plugin import URL (%s)", err)
+		return nil, fmt.Errorf("invalid plugin import URL (%s)", err)
 	}
 
 	if uri.Scheme == "" {
-		return fmt.Errorf("invalid plugin import URL")
+		return nil, fmt.Errorf("invalid plugin import URL")
 	}
 
 	importHandler, ok := importHandlers[uri.Scheme]
 	if !ok {
-		return fmt.Errorf("unsupported plugin URL handler: '%s'", uri.Scheme)
+		return nil, fmt.Errorf("unsupported plugin URL handler: '%s'", uri.Scheme)
+	}
+
+	refresh := mode == LockUpdate
+	locked := lock.Find(pluginURL)
+	if locked != nil && !refresh {
+		pinVersion(uri, locked.Version)
+	}
+
+	a, err := importHandler(ctx, uri, refresh)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import plugin: %s", err)
+	}
+
+	if !remoteProviders[uri.Scheme] {
+		return a, nil
+	}
+
+	a.URL = pluginURL
+	a.LastUsed = time.Now()
+	if err := a.WriteMetadata(); err != nil {
+		return nil, err
+	}
+
+	sum, err := lockfile.Checksum(a.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plugin checksum: %s", err)
+	}
+
+	artifact := lockfile.Artifact{Source: a.Source, SHA256: sum}
+	switch mode {
+	case LockUpdate:
+		lock.Update(pluginURL, a.Version, a.Platform(), artifact)
+		return a, nil
+	case LockInstall:
+		err = lock.Add(pluginURL, a.Version, a.Platform(), artifact)
+	default:
+		err = lock.Verify(pluginURL, a.Version, a.Platform(), artifact)
+	}
+
+	if errors.Is(err, lockfile.ErrNotLocked) {
+		return nil, fmt.Errorf("%w\nUse 'gilbert plugins install' to add plugin to lock file", err)
 	}
 
-	pluginPath, err := importHandler(ctx, uri)
 	if err != nil {
-		return fmt.Errorf("failed to import plugin: %s", err)
+		return nil, fmt.Errorf("%s\nUse 'gilbert plugins update' to update plugins lock file", err)
 	}
 
-	pluginName, handlers, err := loader.LoadPlugin(pluginPath)
+	return a, nil
+}
+
+// pinVersion sets locked plugin version to URL if version is not specified
+func pinVersion(uri *url.URL, version string) {
+	if version == "" || uri.Scheme != github.ProviderName {
+		return
+	}
+
+	q := uri.Query()
+	if q.Get(github.VersionParam) != "" {
+		return
+	}
+
+	q.Set(github.VersionParam, version)
+	uri.RawQuery = q.Encode()
+}
+
+// Import imports plugin from URL, starts it and registers plugin actions as "plugin:action".
+//
+// Returned plugin process should be stopped with Close() when it's not needed anymore.
+func Import(ctx context.Context, pluginURL, projectDir string, lock *lockfile.File, handlers *runner.HandlerSet) (p *loader.Plugin, err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("failed to load plugin from '%s':\n%s", pluginURL, err)
+		}
+	}()
+
+	a, err := Resolve(ctx, pluginURL, lock, LockVerify)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err = loader.LoadPlugin(ctx, a.Path, projectDir)
 	if err != nil {
-		return fmt.Errorf("failed to load plugin: %s", err)
+		return nil, fmt.Errorf("failed to load plugin: %s", err)
 	}
 
-	pluginName = strings.TrimSpace(pluginName)
+	defer func() {
+		if err != nil {
+			_ = p.Close()
+		}
+	}()
+
+	pluginName := p.Name()
 	if pluginName == "" {
-		return errors.New("plugin name should not be empty")
+		return nil, errors.New("plugin name should not be empty")
 	}
 
-	log.Default.Debugf("loader: loaded plugin '%s' from '%s'", pluginName, pluginPath)
+	log.Default.Debugf("loader: loaded plugin '%s' from '%s'", pluginName, a.Path)
 
 	// register plugin action handlers
-	for hName, handler := range handlers {
-		if err := registerPluginAction(pluginName, hName, handler); err != nil {
-			return fmt.Errorf("failed to register action handler, %s", err)
+	for hName, handler := range p.Actions() {
+		if err := registerPluginAction(handlers, pluginName, hName, handler); err != nil {
+			return nil, fmt.Errorf("failed to register action handler, %s", err)
 		}
 	}
-	return nil
+	return p, nil
+}
+
+// ExpandURLs returns plugin URLs declared in manifest with expanded variables
+func ExpandURLs(m *manifest.Manifest, projectDir string) ([]string, error) {
+	if len(m.Plugins) == 0 {
+		return nil, nil
+	}
+
+	s := scope.CreateScope(m.Parser, projectDir, m.Vars)
+	out := make([]string, 0, len(m.Plugins))
+	for _, uri := range m.Plugins {
+		expanded, err := s.ExpandVariables(uri)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load plugins from manifest, %s", err)
+		}
+
+		out = append(out, expanded)
+	}
+
+	return out, nil
 }
@@ -0,0 +1,111 @@
+package plugins
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-gilbert/gilbert/internal/log"
+	"github.com/go-gilbert/gilbert/internal/plugins/lockfile"
+	"github.com/go-gilbert/gilbert/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolve(t *testing.T) {
+	log.UseTestLogger(t)
+	t.Setenv(storage.StoreVarName, t.TempDir())
+
+	contents := "foo"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(contents))
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	pluginURL := srv.URL + "/plugin"
+	lock := lockfile.New(lockfile.Path(t.TempDir()))
+
+	// plugin missing in lock file is rejected unless installed
+	_, err := Resolve(ctx, pluginURL, lock, LockVerify)
+	require.ErrorIs(t, err, lockfile.ErrNotLocked)
+	assert.False(t, lock.Changed())
+
+	a, err := Resolve(ctx, pluginURL, lock, LockInstall)
+	require.NoError(t, err)
+	assert.Equal(t, pluginURL, a.Source)
+	assert.True(t, lock.Changed())
+	require.NotNil(t, lock.Find(pluginURL))
+	require.NoError(t, lock.Save())
+
+	// swapped plugin is detected on a clean machine
+	contents = "bar"
+	t.Setenv(storage.StoreVarName, t.TempDir())
+	_, err = Resolve(ctx, pluginURL, lock, LockVerify)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "checksum mismatch")
+
+	_, err = Resolve(ctx, pluginURL, lock, LockInstall)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "checksum mismatch")
+	lock.Find(pluginURL).Artifacts["other/platform"] = lockfile.Artifact{SHA256: "aaa"}
+
+	// lock file is refreshed, entries of other platforms are kept
+	a, err = Resolve(ctx, pluginURL, lock, LockUpdate)
+	require.NoError(t, err)
+	data, err := os.ReadFile(a.Path)
+	require.NoError(t, err)
+	assert.Equal(t, "bar", string(data))
+	assert.Len(t, lock.Find(pluginURL).Artifacts, 2)
+	require.NoError(t, lock.Save())
+
+	_, err = Resolve(ctx, pluginURL, lock, LockVerify)
+	require.NoError(t, err)
+}
+
+func TestResolve_LocalPlugin(t *testing.T) {
+	pluginPath := filepath.Join(t.TempDir(), "plugin")
+	lock := lockfile.New(lockfile.Path(t.TempDir()))
+
+	a, err := Resolve(context.Background(), "file://"+pluginPath, lock, LockVerify)
+	require.NoError(t, err)
+	assert.Equal(t, pluginPath, a.Path)
+	assert.False(t, lock.Changed(), "local plugins should not be locked")
+}
+
+func TestPinVersion(t *testing.T) {
+	cases := map[string]struct {
+		url      string
+		version  string
+		expected string
+	}{
+		"pin locked version": {
+			url:      "github://github.com/foo/bar",
+			version:  "v1.0.0",
+			expected: "github://github.com/foo/bar?version=v1.0.0",
+		},
+		"keep explicit version": {
+			url:      "github://github.com/foo/bar?version=v2.0.0",
+			version:  "v1.0.0",
+			expected: "github://github.com/foo/bar?version=v2.0.0",
+		},
+		"ignore unversioned source": {
+			url:      "https://example.com/plugin",
+			version:  "v1.0.0",
+			expected: "https://example.com/plugin",
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			uri, err := url.Parse(c.url)
+			require.NoError(t, err)
+			pinVersion(uri, c.version)
+			assert.Equal(t, c.expected, uri.String())
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ErrClosed is returned when connection was closed
+var ErrClosed = errors.New("connection closed")
+
+// Handler handles incoming requests and notifications.
+//
+// Result is ignored for notifications.
+type Handler func(ctx context.Context, c *Conn, method string, params json.RawMessage) (interface{}, error)
+
+// Conn is a bidirectional JSON-RPC connection.
+//
+// Incoming requests are handled in separate goroutines,
+// so long-running request doesn't block other requests.
+// Notifications are handled sequentially before reading the next message.
+type Conn struct {
+	handler Handler
+
+	writeMtx sync.Mutex
+	enc      *json.Encoder
+
+	mtx     sync.Mutex
+	nextID  uint64
+	pending map[uint64]chan *Message
+	done    chan struct{}
+	err     error
+}
+
+// NewConn creates a new connection and starts reading messages
+func NewConn(r io.Reader, w io.Writer, h Handler) *Conn {
+	c := &Conn{
+		handler: h,
+		enc:     json.NewEncoder(w),
+		pending: make(map[uint64]chan *Message),
+		done:    make(chan struct{}),
+	}
+
+	go c.readLoop(json.NewDecoder(r))
+	return c
+}
+
+// Done returns a channel which is closed when connection is closed
+func (c *Conn) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err returns an error which caused connection close
+func (c *Conn) Err() error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.err
+}
+
+// Call sends a request and waits for a response.
+//
+// Response result is decoded into "result" if it's not nil.
+func (c *Conn) Call(ctx context.Context, method string, params, result interface{}) error {
+	c.mtx.Lock()
+	if c.err != nil {
+		c.mtx.Unlock()
+		return c.err
+	}
+
+	c.nextID++
+	id := c.nextID
+	ch := make(chan *Message, 1)
+	c.pending[id] = ch
+	c.mtx.Unlock()
+
+	defer func() {
+		c.mtx.Lock()
+		delete(c.pending, id)
+		c.mtx.Unlock()
+	}()
+
+	if err := c.send(&id, method, params); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.done:
+		return c.Err()
+	case rsp := <-ch:
+		if rsp.Error != nil {
+			return rsp.Error
+		}
+
+		if result == nil || len(rsp.Result) == 0 {
+			return nil
+		}
+
+		if err := json.Unmarshal(rsp.Result, result); err != nil {
+			return fmt.Errorf("invalid %q response: %w", method, err)
+		}
+
+		return nil
+	}
+}
+
+// Notify sends a notification
+func (c *Conn) Notify(method string, params interface{}) error {
+	return c.send(nil, method, params)
+}
+
+func (c *Conn) send(id *uint64, method string, params interface{}) error {
+	msg := &Message{JSONRPC: jsonRPCVersion, ID: id, Method: method}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+
+		msg.Params = data
+	}
+
+	return c.write(msg)
+}
+
+func (c *Conn) write(msg *Message) error {
+	c.writeMtx.Lock()
+	defer c.writeMtx.Unlock()
+	if err := c.enc.Encode(msg); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Conn) reply(id *uint64, result interface{}, err error) {
+	msg := &Message{JSONRPC: jsonRPCVersion, ID: id}
+	if err != nil {
+		rpcErr := &Error{}
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &Error{Code: CodeInternalError, Message: err.Error()}
+		}
+
+		msg.Error = rpcErr
+	} else {
+		data, merr := json.Marshal(result)
+		if merr != nil {
+			msg.Error = &Error{Code: CodeInternalError, Message: merr.Error()}
+		} else {
+			msg.Result = data
+		}
+	}
+
+	// Error is ignored since connection is closed in this case
+	_ = c.write(msg)
+}
+
+func (c *Conn) readLoop(dec *json.Decoder) {
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+	for {
+		msg := &Message{}
+		if err := dec.Decode(msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				err = ErrClosed
+			}
+
+			c.close(err)
+			return
+		}
+
+		if msg.Method == "" {
+			c.handleResponse(msg)
+			continue
+		}
+
+		if msg.ID == nil {
+			// Notifications are handled in order of arrival
+			c.handleRequest(ctx, msg)
+			continue
+		}
+
+		go c.handleRequest(ctx, msg)
+	}
+}
+
+func (c *Conn) handleResponse(msg *Message) {
+	if msg.ID == nil {
+		return
+	}
+
+	// each call receives only one response, duplicate and unknown responses are dropped
+	c.mtx.Lock()
+	ch, ok := c.pending[*msg.ID]
+	delete(c.pending, *msg.ID)
+	c.mtx.Unlock()
+	if ok {
+		ch <- msg
+	}
+}
+
+func (c *Conn) handleRequest(ctx context.Context, msg *Message) {
+	if c.handler == nil {
+		if msg.ID != nil {
+			c.reply(msg.ID, nil, &Error{Code: CodeMethodNotFound, Message: "method not found: " + msg.Method})
+		}
+		return
+	}
+
+	result, err := c.handler(ctx, c, msg.Method, msg.Params)
+	if msg.ID != nil {
+		c.reply(msg.ID, result, err)
+	}
+}
+
+func (c *Conn) close(err error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.err != nil {
+		return
+	}
+
+	c.err = err
+	close(c.done)
+}
@@ -0,0 +1,49 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConn_DuplicateResponse(t *testing.T) {
+	hostR, peerW := io.Pipe()
+	peerR, hostW := io.Pipe()
+	host := NewConn(hostR, hostW, nil)
+	t.Cleanup(func() {
+		_ = peerW.Close()
+		_ = peerR.Close()
+	})
+
+	// peer replies to each request several times and sends response with unknown ID
+	go func() {
+		dec := json.NewDecoder(peerR)
+		enc := json.NewEncoder(peerW)
+		unknownID := uint64(100)
+		for {
+			req := &Message{}
+			if err := dec.Decode(req); err != nil {
+				return
+			}
+
+			for _, id := range []*uint64{req.ID, req.ID, req.ID, &unknownID} {
+				if err := enc.Encode(&Message{JSONRPC: jsonRPCVersion, ID: id, Result: json.RawMessage(`"ok"`)}); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	for i := 0; i < 3; i++ {
+		ctx, cancelFn := context.WithTimeout(context.Background(), 5*time.Second)
+		var result string
+		err := host.Call(ctx, "test", nil, &result)
+		cancelFn()
+		require.NoError(t, err, "call %d", i)
+		require.Equal(t, "ok", result)
+	}
+}
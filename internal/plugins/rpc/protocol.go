@@ -0,0 +1,142 @@
+// Package rpc implements Gilbert plugin protocol.
+//
+// Plugin is a standalone executable which communicates with Gilbert using JSON-RPC 2.0
+// messages over stdin and stdout. Each message is a single line of JSON.
+//
+// Gilbert sends requests to a plugin:
+//
+//   - "initialize" - handshake, plugin returns its name, protocol version and a list of actions.
+//   - "call" - runs an action. Response is sent when action is finished.
+//   - "cancel" - cancels a running action by job ID.
+//   - "shutdown" - asks plugin to exit.
+//
+// Plugin sends "log" notifications to write messages into the job's log.
+//
+// Plugin should not write anything else to stdout, stderr can be used for diagnostic output.
+//
+// Plugin might be also distributed as a WebAssembly module (GOOS=wasip1 GOARCH=wasm),
+// which runs inside an embedded WASI runtime and speaks the same protocol.
+// Module has access only to the project directory, which is mounted as "/project"
+// and passed as a call working directory. Job variables are passed with each call.
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ProtocolVersion is current plugin protocol version
+const ProtocolVersion = 1
+
+const (
+	// MethodInitialize is handshake request method
+	MethodInitialize = "initialize"
+
+	// MethodCall is action call request method
+	MethodCall = "call"
+
+	// MethodCancel is action cancel request method
+	MethodCancel = "cancel"
+
+	// MethodShutdown is plugin shutdown request method
+	MethodShutdown = "shutdown"
+
+	// MethodLog is log notification method
+	MethodLog = "log"
+)
+
+// Standard JSON-RPC error codes
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+const jsonRPCVersion = "2.0"
+
+// Message is JSON-RPC message envelope.
+//
+// Message without ID is a notification, message without method is a response.
+type Message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *uint64         `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error is JSON-RPC error object
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// InitializeParams is "initialize" request params
+type InitializeParams struct {
+	ProtocolVersion int `json:"protocolVersion"`
+}
+
+// InitializeResult is "initialize" response
+type InitializeResult struct {
+	ProtocolVersion int      `json:"protocolVersion"`
+	Name            string   `json:"name"`
+	Actions         []string `json:"actions"`
+}
+
+// CallParams is "call" request params
+type CallParams struct {
+	// JobID identifies the action call in "cancel" request and "log" notifications
+	JobID string `json:"jobId"`
+
+	// Action is action name without plugin prefix
+	Action string `json:"action"`
+
+	// Params is a set of action params with evaluated expressions
+	Params map[string]interface{} `json:"params,omitempty"`
+
+	// Vars is a set of job scope variables with evaluated values
+	Vars map[string]string `json:"vars,omitempty"`
+
+	// WorkDir is project directory
+	WorkDir string `json:"workDir"`
+}
+
+// CancelParams is "cancel" request params
+type CancelParams struct {
+	JobID string `json:"jobId"`
+}
+
+// Level is log message level
+type Level string
+
+const (
+	LevelDebug   Level = "debug"
+	LevelInfo    Level = "info"
+	LevelLog     Level = "log"
+	LevelSuccess Level = "success"
+	LevelWarn    Level = "warn"
+	LevelError   Level = "error"
+)
+
+// LogParams is "log" notification params
+type LogParams struct {
+	JobID   string `json:"jobId"`
+	Level   Level  `json:"level"`
+	Message string `json:"message"`
+}
+
+// CheckVersion checks if protocol version is supported
+func CheckVersion(version int) error {
+	if version != ProtocolVersion {
+		return fmt.Errorf("unsupported plugin protocol version %d (expected %d)", version, ProtocolVersion)
+	}
+
+	return nil
+}
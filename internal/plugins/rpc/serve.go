@@ -0,0 +1,133 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// ActionFunc is plugin action implementation.
+//
+// Context is cancelled when Gilbert requests action cancel.
+type ActionFunc func(ctx context.Context, call *ActionCall) error
+
+// ActionCall contains action call params and allows to write messages into job's log
+type ActionCall struct {
+	CallParams
+	conn *Conn
+}
+
+// Logf sends a log message with specified level
+func (c *ActionCall) Logf(level Level, format string, args ...interface{}) {
+	_ = c.conn.Notify(MethodLog, LogParams{
+		JobID:   c.JobID,
+		Level:   level,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// Plugin is plugin-side protocol implementation
+type Plugin struct {
+	// Name is plugin name
+	Name string
+
+	// Actions is a set of plugin actions
+	Actions map[string]ActionFunc
+
+	mtx      sync.Mutex
+	jobs     map[string]context.CancelFunc
+	shutdown chan struct{}
+	once     sync.Once
+}
+
+// Serve handles requests from Gilbert until shutdown request or input end.
+//
+// Plugin executable usually calls it with os.Stdin and os.Stdout.
+func (p *Plugin) Serve(r io.Reader, w io.Writer) error {
+	p.jobs = make(map[string]context.CancelFunc)
+	p.shutdown = make(chan struct{})
+
+	conn := NewConn(r, w, p.handle)
+
+	select {
+	case <-p.shutdown:
+		return nil
+	case <-conn.Done():
+		if err := conn.Err(); !errors.Is(err, ErrClosed) {
+			return err
+		}
+
+		return nil
+	}
+}
+
+func (p *Plugin) handle(ctx context.Context, conn *Conn, method string, params json.RawMessage) (interface{}, error) {
+	switch method {
+	case MethodInitialize:
+		var req InitializeParams
+		if err := json.Unmarshal(params, &req); err != nil {
+			return nil, &Error{Code: CodeInvalidParams, Message: err.Error()}
+		}
+
+		actions := make([]string, 0, len(p.Actions))
+		for name := range p.Actions {
+			actions = append(actions, name)
+		}
+
+		sort.Strings(actions)
+		return InitializeResult{ProtocolVersion: ProtocolVersion, Name: p.Name, Actions: actions}, nil
+	case MethodCall:
+		call := &ActionCall{conn: conn}
+		if err := json.Unmarshal(params, &call.CallParams); err != nil {
+			return nil, &Error{Code: CodeInvalidParams, Message: err.Error()}
+		}
+
+		return struct{}{}, p.call(ctx, call)
+	case MethodCancel:
+		var req CancelParams
+		if err := json.Unmarshal(params, &req); err != nil {
+			return nil, &Error{Code: CodeInvalidParams, Message: err.Error()}
+		}
+
+		p.mtx.Lock()
+		cancelFn, ok := p.jobs[req.JobID]
+		p.mtx.Unlock()
+		if ok {
+			cancelFn()
+		}
+
+		return struct{}{}, nil
+	case MethodShutdown:
+		p.once.Do(func() {
+			close(p.shutdown)
+		})
+		return struct{}{}, nil
+	default:
+		return nil, &Error{Code: CodeMethodNotFound, Message: "method not found: " + method}
+	}
+}
+
+func (p *Plugin) call(ctx context.Context, call *ActionCall) error {
+	fn, ok := p.Actions[call.Action]
+	if !ok {
+		return fmt.Errorf("action %q is not provided by plugin %q", call.Action, p.Name)
+	}
+
+	ctx, cancelFn := context.WithCancel(ctx)
+	p.mtx.Lock()
+	p.jobs[call.JobID] = cancelFn
+	p.mtx.Unlock()
+
+	defer func() {
+		cancelFn()
+		p.mtx.Lock()
+		delete(p.jobs, call.JobID)
+		p.mtx.Unlock()
+	}()
+
+	return fn(ctx, call)
+}
@@ -0,0 +1,93 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlugin_Serve(t *testing.T) {
+	hostR, pluginW := io.Pipe()
+	pluginR, hostW := io.Pipe()
+
+	p := &Plugin{
+		Name: "test",
+		Actions: map[string]ActionFunc{
+			"hello": func(_ context.Context, call *ActionCall) error {
+				call.Logf(LevelInfo, "hello, %v", call.Params["name"])
+				return nil
+			},
+			"fail": func(context.Context, *ActionCall) error {
+				return errors.New("action failed")
+			},
+			"wait": func(ctx context.Context, _ *ActionCall) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		},
+	}
+
+	served := make(chan error, 1)
+	go func() {
+		served <- p.Serve(pluginR, pluginW)
+	}()
+
+	mtx := sync.Mutex{}
+	var logs []LogParams
+	host := NewConn(hostR, hostW, func(_ context.Context, _ *Conn, method string, params json.RawMessage) (interface{}, error) {
+		require.Equal(t, MethodLog, method)
+		var msg LogParams
+		require.NoError(t, json.Unmarshal(params, &msg))
+		mtx.Lock()
+		logs = append(logs, msg)
+		mtx.Unlock()
+		return nil, nil
+	})
+
+	ctx := context.Background()
+	var info InitializeResult
+	require.NoError(t, host.Call(ctx, MethodInitialize, InitializeParams{ProtocolVersion: ProtocolVersion}, &info))
+	assert.Equal(t, InitializeResult{
+		ProtocolVersion: ProtocolVersion,
+		Name:            "test",
+		Actions:         []string{"fail", "hello", "wait"},
+	}, info)
+
+	require.NoError(t, host.Call(ctx, MethodCall, CallParams{
+		JobID:  "1",
+		Action: "hello",
+		Params: map[string]interface{}{"name": "world"},
+	}, nil))
+	mtx.Lock()
+	assert.Equal(t, []LogParams{{JobID: "1", Level: LevelInfo, Message: "hello, world"}}, logs)
+	mtx.Unlock()
+
+	err := host.Call(ctx, MethodCall, CallParams{JobID: "2", Action: "fail"}, nil)
+	assert.EqualError(t, err, "action failed")
+
+	err = host.Call(ctx, MethodCall, CallParams{JobID: "3", Action: "foo"}, nil)
+	assert.EqualError(t, err, `action "foo" is not provided by plugin "test"`)
+
+	err = host.Call(ctx, "foo", nil, nil)
+	assert.EqualError(t, err, "method not found: foo")
+
+	// cancel running action
+	result := make(chan error, 1)
+	go func() {
+		result <- host.Call(ctx, MethodCall, CallParams{JobID: "4", Action: "wait"}, nil)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, host.Call(ctx, MethodCancel, CancelParams{JobID: "4"}, nil))
+	assert.EqualError(t, <-result, context.Canceled.Error())
+
+	require.NoError(t, host.Call(ctx, MethodShutdown, nil, nil))
+	require.NoError(t, <-served)
+}
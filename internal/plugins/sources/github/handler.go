@@ -17,53 +17,56 @@ import (
 	"github.com/google/go-github/v25/github"
 )
 
-// GetPlugin retrieves plugin from GitHub
-func GetPlugin(ctx context.Context, uri *url.URL) (string, error) {
+// GetPlugin retrieves plugin from GitHub.
+//
+// Cached plugin is downloaded again if refresh is true.
+func GetPlugin(ctx context.Context, uri *url.URL, refresh bool) (*support.Artifact, error) {
 	dc, err := readURL(ctx, uri)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	dir, err := storage.Path(storage.Plugins, dc.pkg.directory())
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	pluginPath := filepath.Join(dir, dc.pkg.fileName())
 	exists, err := fs.Exists(pluginPath)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	if !exists {
-		log.Default.Debugf("github: plugin is not cached and need to be downloaded")
-		log.Default.Debugf("github: init plugin directory: '%s'", dir)
-		if err = os.MkdirAll(dir, support.PluginPermissions); err != nil {
-			return "", err
-		}
+	if exists && !refresh {
+		return support.ReadArtifact(pluginPath)
+	}
 
-		asset, err := getPluginRelease(ctx, dc.ghClient, dc.pkg)
-		if err != nil {
-			return "", err
-		}
+	log.Default.Debugf("github: plugin is not cached and need to be downloaded")
+	log.Default.Debugf("github: init plugin directory: '%s'", dir)
+	if err = os.MkdirAll(dir, support.PluginPermissions); err != nil {
+		return nil, err
+	}
 
-		assetURL := asset.GetBrowserDownloadURL()
-		if assetURL == "" {
-			return "", errors.New("missing asset download URL")
-		}
+	rel, asset, err := getPluginRelease(ctx, dc.ghClient, dc.pkg)
+	if err != nil {
+		return nil, err
+	}
 
-		log.Default.Debugf("github: downloading plugin from '%s'...", assetURL)
-		if err := web.ProgressDownloadFile(dc.httpClient, assetURL, pluginPath); err != nil {
-			return "", err
-		}
+	assetURL := asset.GetBrowserDownloadURL()
+	if assetURL == "" {
+		return nil, errors.New("missing asset download URL")
+	}
+
+	log.Default.Debugf("github: downloading plugin from '%s'...", assetURL)
+	if err := web.ProgressDownloadFile(dc.httpClient, assetURL, pluginPath); err != nil {
+		return nil, err
 	}
 
-	return pluginPath, nil
+	return &support.Artifact{Path: pluginPath, Source: assetURL, Version: rel.GetTagName()}, nil
 }
 
-func getPluginRelease(ctx context.Context, client *github.Client, pkg packageQuery) (asset *github.ReleaseAsset, err error) {
+func getPluginRelease(ctx context.Context, client *github.Client, pkg packageQuery) (rel *github.RepositoryRelease, asset *github.ReleaseAsset, err error) {
 	log.Default.Logf("Downloading plugin from GitHub repo '%s/%s'", pkg.owner, pkg.repo)
-	var rel *github.RepositoryRelease
 	if pkg.version == latestVersion {
 		rel, _, err = client.Repositories.GetLatestRelease(ctx, pkg.owner, pkg.repo)
 	} else {
@@ -71,20 +74,29 @@ func getPluginRelease(ctx context.Context, client *github.Client, pkg packageQue
 	}
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to get release information from GitHub, %s", err)
+		return nil, nil, fmt.Errorf("failed to get release information from GitHub, %s", err)
 	}
 
 	assetName := pkg.fileName()
 	log.Default.Debugf("github: trying to find release asset '%s'", assetName)
-	return findReleaseAsset(assetName, rel.Assets)
+	asset, ok := findReleaseAsset(assetName, rel.Assets)
+	if ok {
+		return rel, asset, nil
+	}
+
+	if pkg.wasm {
+		return nil, nil, fmt.Errorf("repository release does not contain WebAssembly module '%s'", assetName)
+	}
+
+	return nil, nil, fmt.Errorf("repository does not contain release for platform %s %s", runtime.GOOS, runtime.GOARCH)
 }
 
-func findReleaseAsset(fileName string, assets []github.ReleaseAsset) (*github.ReleaseAsset, error) {
+func findReleaseAsset(fileName string, assets []github.ReleaseAsset) (*github.ReleaseAsset, bool) {
 	for _, asset := range assets {
 		if asset.GetName() == fileName {
-			return &asset, nil
+			return &asset, true
 		}
 	}
 
-	return nil, fmt.Errorf("repository does not contain release for platform %s %s", runtime.GOOS, runtime.GOARCH)
+	return nil, false
 }
@@ -26,11 +26,13 @@ const (
 	pkgPathSize     = 2 // /owner/repo
 	pathDelimiter   = "/"
 	protocolParam   = "protocol"
-	versionParam    = "version"
 	tokenParam      = "token"
 
 	// ProviderName is GitHub provider name
 	ProviderName = "github"
+
+	// VersionParam is URL param which specifies plugin release version
+	VersionParam = "version"
 )
 
 var (
@@ -49,9 +51,15 @@ type packageQuery struct {
 	repo     string
 	version  string
 	location string
+	wasm     bool
 }
 
 func (p *packageQuery) fileName() string {
+	if p.wasm {
+		// WebAssembly plugin is platform-independent
+		return p.repo + support.WasmExtension
+	}
+
 	name := fmt.Sprintf("%s_%s-%s", p.repo, runtime.GOOS, runtime.GOARCH)
 	return support.AddPluginExtension(name)
 }
@@ -102,12 +110,13 @@ func readURL(ctx context.Context, uri *url.URL) (*downloadContext, error) {
 		dc.ghClient = github.NewClient(dc.httpClient)
 	}
 
-	if ver := uri.Query().Get(versionParam); ver != "" {
+	if ver := uri.Query().Get(VersionParam); ver != "" {
 		dc.pkg.version = ver
 	} else {
 		dc.pkg.version = latestVersion
 	}
 
+	dc.pkg.wasm = support.IsWasmRuntime(uri)
 	dc.pkg.location = path.Join(uri.Hostname(), uri.Path, dc.pkg.version)
 	return &dc, err
 }
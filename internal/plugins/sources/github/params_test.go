@@ -89,6 +89,18 @@ func TestReadUrl(t *testing.T) {
 				},
 			},
 		},
+		"should parse WebAssembly runtime param": {
+			url: "github://github.com/foo/bar?runtime=wasm",
+			expected: expected{
+				pkg: packageQuery{
+					owner:    "foo",
+					repo:     "bar",
+					version:  "latest",
+					location: "github.com/foo/bar/latest",
+					wasm:     true,
+				},
+			},
+		},
 		"should parse simple GH enterprise url": {
 			skip: false,
 			url:  "github://github.example.com/foo/bar",
@@ -0,0 +1,141 @@
+package gopkg
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// checksumExt is extension of plugin source checksum file stored next to plugin
+const checksumExt = ".sum"
+
+// moduleFiles are module files which affect plugin build
+var moduleFiles = []string{"go.mod", "go.sum"}
+
+// savedChecksum returns source checksum saved after the last plugin build
+func savedChecksum(ic *importContext) string {
+	data, err := os.ReadFile(ic.filePath + checksumExt)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(data))
+}
+
+func saveChecksum(ic *importContext) error {
+	return os.WriteFile(ic.filePath+checksumExt, []byte(ic.checksum), 0644)
+}
+
+// sourceChecksum returns checksum of plugin package Go files, module files and Go toolchain version.
+//
+// Go files of nested packages are included since they might be imported by plugin package.
+var sourceChecksum = func(ctx context.Context, ic *importContext) (string, error) {
+	ver, err := goVersion(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n", ver)
+	if ic.wasm {
+		fmt.Fprintf(h, "%s\n", strings.Join(wasmBuildEnv, " "))
+	}
+
+	err = filepath.WalkDir(ic.pkgPath, func(fileName string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if fileName != ic.pkgPath && isIgnoredDir(d.Name()) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if filepath.Ext(fileName) != ".go" || strings.HasSuffix(fileName, "_test.go") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(ic.pkgPath, fileName)
+		if err != nil {
+			return err
+		}
+
+		return hashFile(h, filepath.ToSlash(rel), fileName)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	modDir, ok := findModuleRoot(ic.pkgPath)
+	if ok {
+		for _, name := range moduleFiles {
+			err := hashFile(h, name, filepath.Join(modDir, name))
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return "", err
+			}
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// goVersion returns Go toolchain version
+var goVersion = func(ctx context.Context) (string, error) {
+	out, err := exec.CommandContext(ctx, "go", "env", "GOVERSION").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get Go version, %s", err)
+	}
+
+	return string(bytes.TrimSpace(out)), nil
+}
+
+// isIgnoredDir checks if directory is ignored by Go tool
+func isIgnoredDir(name string) bool {
+	return name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
+
+// findModuleRoot returns directory of go.mod file which contains the package
+func findModuleRoot(dir string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, true
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+
+		dir = parent
+	}
+}
+
+func hashFile(h hash.Hash, name, fileName string) error {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+	fmt.Fprintf(h, "%s\n", name)
+	_, err = io.Copy(h, f)
+	return err
+}
@@ -0,0 +1,119 @@
+package gopkg
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-gilbert/gilbert/internal/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceChecksum(t *testing.T) {
+	log.UseTestLogger(t)
+	version := "go1.22.0"
+	before := goVersion
+	goVersion = func(context.Context) (string, error) {
+		return version, nil
+	}
+	defer func() {
+		goVersion = before
+	}()
+
+	modDir := t.TempDir()
+	pkgDir := filepath.Join(modDir, "cmd", "plugin")
+	writeFile := func(name, data string) {
+		fileName := filepath.Join(modDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(fileName), 0755))
+		require.NoError(t, os.WriteFile(fileName, []byte(data), 0644))
+	}
+
+	writeFile("go.mod", "module example.com/plugin")
+	writeFile("cmd/plugin/main.go", "package main")
+	writeFile("cmd/plugin/internal/foo.go", "package internal")
+
+	ic := &importContext{pkgPath: pkgDir, filePath: filepath.Join(t.TempDir(), "plugin")}
+	checksum := func() string {
+		sum, err := sourceChecksum(context.Background(), ic)
+		require.NoError(t, err)
+		return sum
+	}
+
+	sum := checksum()
+	cases := []struct {
+		name    string
+		change  func()
+		changed bool
+	}{
+		{
+			name:   "test files are ignored",
+			change: func() { writeFile("cmd/plugin/main_test.go", "package main") },
+		},
+		{
+			name:   "testdata is ignored",
+			change: func() { writeFile("cmd/plugin/testdata/foo.go", "package foo") },
+		},
+		{
+			name:   "files outside of package are ignored",
+			change: func() { writeFile("foo.go", "package foo") },
+		},
+		{
+			name:    "package file changed",
+			change:  func() { writeFile("cmd/plugin/main.go", "package main\n\nfunc main() {}") },
+			changed: true,
+		},
+		{
+			name:    "nested package changed",
+			change:  func() { writeFile("cmd/plugin/internal/foo.go", "package internal\n") },
+			changed: true,
+		},
+		{
+			name:    "go.sum added",
+			change:  func() { writeFile("go.sum", "example.com/foo v1.0.0 h1:abc") },
+			changed: true,
+		},
+		{
+			name:    "toolchain changed",
+			change:  func() { version = "go1.23.0" },
+			changed: true,
+		},
+		{
+			name:    "runtime changed",
+			change:  func() { ic.wasm = true },
+			changed: true,
+		},
+	}
+
+	for _, c := range cases {
+		c.change()
+		got := checksum()
+		if c.changed {
+			assert.NotEqual(t, sum, got, c.name)
+		} else {
+			assert.Equal(t, sum, got, c.name)
+		}
+		sum = got
+	}
+}
+
+func TestPluginCached(t *testing.T) {
+	log.UseTestLogger(t)
+	ic := &importContext{
+		pkgPath:  "foo/bar",
+		filePath: filepath.Join(t.TempDir(), "bar"),
+		checksum: "foo",
+	}
+
+	assert.False(t, pluginCached(ic), "missing plugin")
+
+	require.NoError(t, os.WriteFile(ic.filePath, []byte("plugin"), 0755))
+	assert.False(t, pluginCached(ic), "missing checksum")
+
+	require.NoError(t, saveChecksum(ic))
+	assert.True(t, pluginCached(ic))
+
+	ic.checksum = "bar"
+	assert.False(t, pluginCached(ic), "changed checksum")
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/go-gilbert/gilbert/internal/support/fs"
 	"github.com/go-gilbert/gilbert/internal/support/shell"
 	"net/url"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"strconv"
@@ -22,6 +23,10 @@ type importContext struct {
 	fileName string
 	filePath string
 	rebuild  bool
+	wasm     bool
+
+	// checksum is plugin source checksum
+	checksum string
 }
 
 const (
@@ -31,9 +36,16 @@ const (
 	rebuildParam = "rebuild"
 )
 
+// wasmBuildEnv is build environment for WebAssembly plugins
+var wasmBuildEnv = []string{"GOOS=wasip1", "GOARCH=wasm"}
+
 func newImportContext(uri *url.URL) (*importContext, error) {
 	pkgPath := filepath.Clean(filepath.Join(uri.Host + "/" + uri.Path))
+	wasm := support.IsWasmRuntime(uri)
 	fName := support.AddPluginExtension(filepath.Base(pkgPath))
+	if wasm {
+		fName = filepath.Base(pkgPath) + support.WasmExtension
+	}
 
 	hasher := md5.New()
 	if _, err := hasher.Write([]byte(pkgPath)); err != nil {
@@ -50,26 +62,38 @@ func newImportContext(uri *url.URL) (*importContext, error) {
 		fileName: fName,
 		filePath: filepath.Join(pluginDir, fName),
 		rebuild:  rebuild,
+		wasm:     wasm,
 	}, nil
 }
 
-// GetPlugin returns plugin from URL
-func GetPlugin(ctx context.Context, uri *url.URL) (string, error) {
+// GetPlugin returns plugin from URL.
+//
+// Plugin is built again if refresh is true.
+func GetPlugin(ctx context.Context, uri *url.URL, refresh bool) (*support.Artifact, error) {
 	ic, err := newImportContext(uri)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	ic.checksum, err = sourceChecksum(ctx, ic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check plugin package source (%s)", err)
 	}
 
-	// TODO: automatically check if plugin source changed
-	if pluginCached(ic) && !ic.rebuild {
-		return ic.filePath, nil
+	a := &support.Artifact{Path: ic.filePath, Source: ic.pkgPath}
+	if pluginCached(ic) && !ic.rebuild && !refresh {
+		return a, nil
 	}
 
 	if err := buildPlugin(ctx, ic); err != nil {
-		return "", fmt.Errorf("failed to build plugin package (%s)", err)
+		return nil, fmt.Errorf("failed to build plugin package (%s)", err)
+	}
+
+	if err := saveChecksum(ic); err != nil {
+		log.Default.Warnf("goloader: failed to save plugin source checksum, %s", err)
 	}
 
-	return ic.filePath, nil
+	return a, nil
 }
 
 func buildPlugin(ctx context.Context, ic *importContext) error {
@@ -77,6 +101,9 @@ func buildPlugin(ctx context.Context, ic *importContext) error {
 
 	cmd := exec.CommandContext(ctx, "go", "build", "-buildmode", support.BuildMode, "-o", ic.filePath, ".")
 	cmd.Dir = ic.pkgPath
+	if ic.wasm {
+		cmd.Env = append(os.Environ(), wasmBuildEnv...)
+	}
 
 	cmd.Stdout = log.Default
 	cmd.Stderr = log.Default.ErrorWriter()
@@ -101,11 +128,16 @@ var pluginCached = func(ic *importContext) bool {
 		log.Default.Warnf("goloader: failed to check if plugin exists, %s", err)
 	}
 
-	if exists && err == nil {
-		return true
+	if !exists || err != nil {
+		return false
+	}
+
+	if savedChecksum(ic) != ic.checksum {
+		log.Default.Logf("Plugin package '%s' was changed and will be rebuilt", ic.pkgPath)
+		return false
 	}
 
-	return false
+	return true
 }
 
 var runGoCommand = func(cmd *exec.Cmd) error {
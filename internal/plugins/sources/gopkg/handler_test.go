@@ -23,6 +23,16 @@ func mockPluginCacheCheck(fn func(ic *importContext) bool) {
 	}
 }
 
+func mockSourceChecksum(sum string) {
+	before := sourceChecksum
+	sourceChecksum = func(_ context.Context, _ *importContext) (string, error) {
+		defer func() {
+			sourceChecksum = before
+		}()
+		return sum, nil
+	}
+}
+
 func mockCommandRunner(fn func(cmd *exec.Cmd) error) {
 	before := runGoCommand
 	runGoCommand = func(cmd *exec.Cmd) error {
@@ -39,6 +49,7 @@ type expects struct {
 	rebuild  bool
 	err      string
 	build    bool
+	wasm     bool
 }
 
 func TestGetPlugin(t *testing.T) {
@@ -74,6 +85,15 @@ func TestGetPlugin(t *testing.T) {
 				fileName: support.AddPluginExtension("bar"),
 			},
 		},
+		"build WebAssembly plugin if runtime param present": {
+			uri: "go://foo/bar?runtime=wasm",
+			expects: expects{
+				build:    true,
+				wasm:     true,
+				pkgPath:  filepath.Join("foo", "bar"),
+				fileName: "bar.wasm",
+			},
+		},
 		"return error on compile failure": {
 			uri:      "go://foo/bar",
 			cmdError: errors.New("dump error"),
@@ -94,6 +114,7 @@ func TestGetPlugin(t *testing.T) {
 				t.Fatal(err)
 			}
 
+			mockSourceChecksum("checksum")
 			mockPluginCacheCheck(func(ic *importContext) bool {
 				defer func() {
 					if r := recover(); r != nil {
@@ -103,6 +124,8 @@ func TestGetPlugin(t *testing.T) {
 				assert.Equal(t, c.expects.fileName, ic.fileName)
 				assert.Equal(t, c.expects.pkgPath, ic.pkgPath)
 				assert.Equal(t, c.expects.rebuild, ic.rebuild)
+				assert.Equal(t, c.expects.wasm, ic.wasm)
+				assert.Equal(t, "checksum", ic.checksum)
 				return c.pluginExists
 			})
 
@@ -111,6 +134,10 @@ func TestGetPlugin(t *testing.T) {
 					t.Fatal("package build unexpected")
 				}
 
+				if c.expects.wasm {
+					assert.Subset(t, cmd.Env, wasmBuildEnv)
+				}
+
 				// output file path is penultimate
 				outPath := cmd.Args[len(cmd.Args)-2]
 
@@ -121,15 +148,15 @@ func TestGetPlugin(t *testing.T) {
 				return c.cmdError
 			})
 
-			out, err := GetPlugin(context.Background(), uri)
+			a, err := GetPlugin(context.Background(), uri, false)
 			if c.expects.err != "" {
 				assert.EqualError(t, err, c.expects.err)
 				return
 			}
 
 			assert.NoError(t, err)
-			if !strings.Contains(out, c.expects.fileName) {
-				t.Errorf("'%s' is not in '%s'", c.expects.fileName, out)
+			if !strings.Contains(a.Path, c.expects.fileName) {
+				t.Errorf("'%s' is not in '%s'", c.expects.fileName, a.Path)
 				t.Fatalf("library path should contain valid filename!")
 			}
 		})
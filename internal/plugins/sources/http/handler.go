@@ -33,32 +33,41 @@ func getPluginDirectory(uri string) string {
 	return filepath.Join(ProviderName, hex.EncodeToString(hasher.Sum(nil)))
 }
 
-// GetPlugin is web source handler for plugins
-func GetPlugin(ctx context.Context, uri *url.URL) (string, error) {
+// GetPlugin is web source handler for plugins.
+//
+// Cached plugin is downloaded again if refresh is true.
+func GetPlugin(ctx context.Context, uri *url.URL, refresh bool) (*support.Artifact, error) {
 	strURL := uri.String()
 	dir, err := storage.Path(storage.Plugins, getPluginDirectory(strURL))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	// TODO: determine real file name from web response
-	pluginPath := filepath.Join(dir, support.AddPluginExtension(defaultPluginFName))
+	fileName := support.AddPluginExtension(defaultPluginFName)
+	if support.IsWasmRuntime(uri) {
+		fileName = defaultPluginFName + support.WasmExtension
+	}
+
+	pluginPath := filepath.Join(dir, fileName)
 	exists, err := fs.Exists(pluginPath)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	if !exists {
-		log.Default.Debugf("http: init plugin directory: '%s'", dir)
-		if err = os.MkdirAll(dir, support.PluginPermissions); err != nil {
-			return "", err
-		}
+	if exists && !refresh {
+		return support.ReadArtifact(pluginPath)
+	}
+
+	log.Default.Debugf("http: init plugin directory: '%s'", dir)
+	if err = os.MkdirAll(dir, support.PluginPermissions); err != nil {
+		return nil, err
+	}
 
-		log.Default.Logf("Downloading plugin file from '%s'...", strURL)
-		if err := web.ProgressDownloadFile(&http.Client{}, strURL, pluginPath); err != nil {
-			return "", err
-		}
+	log.Default.Logf("Downloading plugin file from '%s'...", strURL)
+	if err := web.ProgressDownloadFile(&http.Client{}, strURL, pluginPath); err != nil {
+		return nil, err
 	}
 
-	return pluginPath, nil
+	return &support.Artifact{Path: pluginPath, Source: strURL}, nil
 }
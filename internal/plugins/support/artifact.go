@@ -0,0 +1,94 @@
+package support
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// metadataExt is extension of plugin metadata file
+const metadataExt = ".json"
+
+// Artifact is a plugin file fetched from plugin source
+type Artifact struct {
+	// Path is plugin file location
+	Path string `json:"-"`
+
+	// Source is a location where plugin was fetched from
+	Source string `json:"source"`
+
+	// Version is resolved plugin version. Empty if plugin source is not versioned.
+	Version string `json:"version,omitempty"`
+
+	// URL is plugin import URL
+	URL string `json:"url,omitempty"`
+
+	// LastUsed is a time when plugin was used last time
+	LastUsed time.Time `json:"lastUsed,omitempty"`
+}
+
+// Platform returns plugin platform name.
+//
+// WebAssembly plugins are platform-independent and have "wasm" platform.
+func (a Artifact) Platform() string {
+	if IsWasmModule(a.Path) {
+		return RuntimeWasm
+	}
+
+	return runtime.GOOS + "/" + runtime.GOARCH
+}
+
+// WriteMetadata saves artifact information next to plugin file
+func (a Artifact) WriteMetadata() error {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(a.Path+metadataExt, data, 0644); err != nil {
+		return fmt.Errorf("failed to save plugin metadata: %w", err)
+	}
+
+	return nil
+}
+
+// ReadArtifact returns information about plugin file saved by WriteMetadata.
+//
+// Only artifact path is returned if plugin has no metadata.
+func ReadArtifact(pluginPath string) (*Artifact, error) {
+	a := &Artifact{Path: pluginPath}
+	data, err := os.ReadFile(pluginPath + metadataExt)
+	if errors.Is(err, os.ErrNotExist) {
+		return a, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, a); err != nil {
+		return nil, fmt.Errorf("invalid plugin metadata file: %w", err)
+	}
+
+	return a, nil
+}
+
+// Remove deletes plugin file and its metadata
+func (a Artifact) Remove() error {
+	for _, name := range []string{a.Path, a.Path + metadataExt} {
+		if err := os.Remove(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IsMetadataFile checks if file is a plugin metadata file
+func IsMetadataFile(fileName string) bool {
+	return strings.HasSuffix(fileName, metadataExt)
+}
@@ -1,6 +1,46 @@
 package support
 
-import "os"
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
 
 // PluginPermissions is permissions for plugin assets
 var PluginPermissions = os.FileMode(0755)
+
+// BuildMode is build mode for plugins.
+//
+// Plugins are standalone executables which communicate with Gilbert using plugin protocol.
+var BuildMode = "exe"
+
+const (
+	// WasmExtension is file extension of WebAssembly plugins
+	WasmExtension = ".wasm"
+
+	// RuntimeParam is plugin URL param which specifies plugin runtime
+	RuntimeParam = "runtime"
+
+	// RuntimeWasm is runtime param value for WebAssembly plugins
+	RuntimeWasm = "wasm"
+
+	// WasmCacheDir is a directory in plugins storage for compiled WebAssembly modules
+	WasmCacheDir = "wasm-cache"
+)
+
+// IsWasmModule checks if plugin file is a WebAssembly module
+func IsWasmModule(fileName string) bool {
+	return strings.EqualFold(filepath.Ext(fileName), WasmExtension)
+}
+
+// IsWasmRuntime checks if plugin URL requests a WebAssembly plugin.
+//
+// WebAssembly plugin is requested with "runtime=wasm" URL param or by ".wasm" file extension.
+func IsWasmRuntime(uri *url.URL) bool {
+	if strings.EqualFold(uri.Query().Get(RuntimeParam), RuntimeWasm) {
+		return true
+	}
+
+	return IsWasmModule(uri.Path)
+}
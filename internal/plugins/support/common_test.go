@@ -0,0 +1,28 @@
+package support
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsWasmRuntime(t *testing.T) {
+	cases := map[string]bool{
+		"go://foo/bar":                             false,
+		"go://foo/bar?runtime=wasm":                true,
+		"github://github.com/foo/bar?runtime=WASM": true,
+		"https://example.com/plugin.wasm":          true,
+		"https://example.com/plugin.wasm?v=1":      true,
+		"https://example.com/plugin.exe":           false,
+	}
+
+	for rawURL, expected := range cases {
+		t.Run(rawURL, func(t *testing.T) {
+			uri, err := url.Parse(rawURL)
+			require.NoError(t, err)
+			assert.Equal(t, expected, IsWasmRuntime(uri))
+		})
+	}
+}
@@ -1,15 +1,9 @@
-// +build linux darwin
+//go:build !windows
+// +build !windows
 
 package support
 
-/////////////////////////////////////////////////////
-// Codebase for platforms that supports Go plugins //
-/////////////////////////////////////////////////////
-
 // AddPluginExtension adds plugin extension format to the provided plugin file
 func AddPluginExtension(fileName string) string {
-	return fileName + ".so"
+	return fileName
 }
-
-// BuildMode is build mode for plugins
-var BuildMode = "plugin"
@@ -1,11 +1,9 @@
+//go:build windows
 // +build windows
 
 package support
 
-// PluginExtension adds plugin extension format to the provided plugin file
+// AddPluginExtension adds plugin extension format to the provided plugin file
 func AddPluginExtension(fileName string) string {
 	return fileName + ".exe"
 }
-
-// BuildMode is build mode for plugins
-var BuildMode = "exe"
@@ -2,55 +2,148 @@ package runner
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/go-gilbert/gilbert/internal/log"
+	"github.com/go-gilbert/gilbert/internal/manifest"
 	"github.com/go-gilbert/gilbert/internal/runner/job"
 )
 
+// asyncJobError is an error returned by async job
+type asyncJobError struct {
+	step        int
+	description string
+	err         error
+}
+
+func (e asyncJobError) Error() string {
+	return fmt.Sprintf("async job %d (%s): %s", e.step, e.description, e.err)
+}
+
+func (e asyncJobError) Unwrap() error {
+	return e.err
+}
+
 // asyncJobTracker tracks state of async jobs
 type asyncJobTracker struct {
-	wg     *sync.WaitGroup
-	errors chan error
-	log    log.Logger
-	ctx    context.Context
-}
-
-func newAsyncJobTracker(ctx context.Context, l log.Logger, poolSize int) *asyncJobTracker {
-	return &asyncJobTracker{
-		wg:     &sync.WaitGroup{},
-		errors: make(chan error, poolSize),
-		log:    l,
-		ctx:    ctx,
+	wg       *sync.WaitGroup
+	log      log.Logger
+	ctx      context.Context
+	cancelFn context.CancelFunc
+	policy   manifest.AsyncFailurePolicy
+	slots    chan struct{}
+
+	mtx    sync.Mutex
+	errors []asyncJobError
+}
+
+// newAsyncJobTracker creates a new async jobs tracker.
+//
+// All async jobs should use tracker's context to be cancelled
+// if failure policy requires that.
+//
+// "maxParallel" limits count of async jobs running at the same time, zero means no limit.
+func newAsyncJobTracker(ctx context.Context, l log.Logger, policy manifest.AsyncFailurePolicy, maxParallel int) *asyncJobTracker {
+	ctx, cancelFn := context.WithCancel(ctx)
+	t := &asyncJobTracker{
+		wg:       &sync.WaitGroup{},
+		log:      l,
+		ctx:      ctx,
+		cancelFn: cancelFn,
+		policy:   policy,
+	}
+
+	if maxParallel > 0 {
+		t.slots = make(chan struct{}, maxParallel)
 	}
+
+	return t
+}
+
+// context returns a shared context for async jobs
+func (t *asyncJobTracker) context() context.Context {
+	return t.ctx
 }
 
-// trackAsyncJobs tracks errors from async jobs
-func (t *asyncJobTracker) trackAsyncJobs() {
-	select {
-	case err, ok := <-t.errors:
-		if ok && err != nil {
-			t.log.Errorf("ERROR: async job returned error: %s", err)
+// start starts async job in a separate goroutine and tracks its result.
+//
+// If parallel jobs limit is reached, blocks until one of running jobs is finished.
+// Job is not started if tracker context was cancelled while waiting.
+func (t *asyncJobTracker) start(ctx *job.RunContext, step int, description string, fn func()) {
+	if t.slots != nil {
+		select {
+		case t.slots <- struct{}{}:
+		case <-t.ctx.Done():
+			t.log.Debugf("runner: async job %d was not started: %s", step, t.ctx.Err())
+			return
 		}
-	case <-t.ctx.Done():
-		return
 	}
+
+	t.trackJob(ctx, step, description)
+	go func() {
+		if t.slots != nil {
+			defer func() { <-t.slots }()
+		}
+
+		fn()
+	}()
 }
 
-// decorateJobContext binds tracker to job context
-func (t *asyncJobTracker) decorateJobContext(ctx *job.RunContext) {
+// trackJob collects result of async job.
+//
+// "step" and "description" are used to identify the job in error message.
+func (t *asyncJobTracker) trackJob(ctx *job.RunContext, step int, description string) {
 	t.wg.Add(1)
-	ctx.SetWaitGroup(t.wg)
-	ctx.SetErrorChannel(t.errors)
+	go func() {
+		defer t.wg.Done()
+		err, ok := <-ctx.Errors()
+		if !ok || err == nil {
+			return
+		}
+
+		t.log.Errorf("ERROR: async job %d (%s) returned error: %s", step, description, err)
+		t.mtx.Lock()
+		t.errors = append(t.errors, asyncJobError{
+			step:        step,
+			description: description,
+			err:         err,
+		})
+		t.mtx.Unlock()
+
+		if t.policy == manifest.AsyncFailureCancelSiblings {
+			t.log.Warn("Cancelling other async jobs...")
+			t.cancelFn()
+		}
+	}()
 }
 
-// wait waits until all async jobs complete
+// wait waits until all async jobs complete and returns errors of all failed jobs.
+//
+// Tracker context is released after all jobs are complete.
 func (t *asyncJobTracker) wait() error {
+	defer t.cancelFn()
+
 	// Wait for unfinished async tasks
 	// and collect results from async jobs
 	t.wg.Wait()
-	close(t.errors)
 
-	// TODO: report if any of async jobs failed
-	return nil
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if len(t.errors) == 0 {
+		return nil
+	}
+
+	sort.Slice(t.errors, func(i, j int) bool {
+		return t.errors[i].step < t.errors[j].step
+	})
+
+	errs := make([]error, 0, len(t.errors))
+	for _, err := range t.errors {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
 }
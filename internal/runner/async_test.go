@@ -3,26 +3,62 @@ package runner
 import (
 	"context"
 	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-gilbert/gilbert/internal/manifest"
 	"github.com/go-gilbert/gilbert/internal/runner/job"
 	"github.com/go-gilbert/gilbert/internal/support/test"
 	"github.com/stretchr/testify/assert"
-	"testing"
-	"time"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTrackAsyncJobs(t *testing.T) {
 	l := &test.Log{T: t}
 	ctx := context.Background()
-	tr := newAsyncJobTracker(ctx, l, 1)
-	rtx := job.NewRunContext(ctx, nil, l)
+	tr := newAsyncJobTracker(ctx, l, manifest.AsyncFailureWaitAll, 0)
+	expected := errors.New("foo")
 
-	tr.decorateJobContext(rtx)
-	go tr.trackAsyncJobs()
+	rtx1 := job.NewRunContext(tr.context(), nil, l)
+	rtx2 := job.NewRunContext(tr.context(), nil, l)
+	rtx3 := job.NewRunContext(tr.context(), nil, l)
+	tr.trackJob(rtx1, 1, "first")
+	tr.trackJob(rtx2, 2, "second")
+	tr.trackJob(rtx3, 3, "third")
 
+	siblingErr := make(chan error, 1)
 	go func() {
 		time.Sleep(time.Millisecond * 300)
-		rtx.Result(errors.New("foo"))
+		rtx3.Result(errors.New("bar"))
+		rtx1.Result(expected)
+		time.Sleep(time.Millisecond * 100)
+		siblingErr <- rtx2.Context().Err()
+		rtx2.Success()
+	}()
+
+	err := tr.wait()
+	require.Error(t, err)
+	assert.ErrorIs(t, err, expected)
+	assert.Equal(t, "async job 1 (first): foo\nasync job 3 (third): bar", err.Error())
+	assert.NoError(t, <-siblingErr, "context shouldn't be cancelled")
+	assert.Error(t, tr.context().Err(), "context should be released after all jobs are complete")
+}
+
+func TestTrackAsyncJobs_CancelSiblings(t *testing.T) {
+	l := &test.Log{T: t}
+	tr := newAsyncJobTracker(context.Background(), l, manifest.AsyncFailureCancelSiblings, 0)
+
+	failed := job.NewRunContext(tr.context(), nil, l)
+	sibling := job.NewRunContext(tr.context(), nil, l)
+	tr.trackJob(failed, 1, "failed")
+	tr.trackJob(sibling, 2, "sibling")
+
+	go func() {
+		<-sibling.Context().Done()
+		sibling.Success()
 	}()
 
-	assert.NoError(t, tr.wait())
+	failed.Result(errors.New("foo"))
+	assert.EqualError(t, tr.wait(), "async job 1 (failed): foo")
+	assert.Error(t, sibling.Context().Err(), "sibling job should be cancelled")
 }
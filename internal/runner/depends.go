@@ -0,0 +1,110 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/go-gilbert/gilbert/internal/manifest"
+)
+
+// dependencyNode holds state of a single prerequisite task
+type dependencyNode struct {
+	once sync.Once
+	err  error
+}
+
+// depsKey is context key of dependency tracker of current task runner invocation
+type depsKey struct{}
+
+// dependencyTracker runs tasks declared in "depends" section.
+//
+// Each prerequisite task runs only once per task runner invocation (TaskRunner.Run call),
+// even if it's required by several tasks.
+type dependencyTracker struct {
+	runner *TaskRunner
+	ctx    context.Context
+	vars   manifest.Vars
+	mtx    sync.Mutex
+	nodes  map[string]*dependencyNode
+}
+
+// newDependencyTracker creates a new tracker for task runner invocation.
+//
+// Tracker is attached to the context, which should be used by jobs of invocation
+// to share the tracker with sub-tasks.
+func newDependencyTracker(ctx context.Context, r *TaskRunner, vars manifest.Vars) *dependencyTracker {
+	d := &dependencyTracker{
+		runner: r,
+		vars:   vars,
+		nodes:  make(map[string]*dependencyNode),
+	}
+
+	d.ctx = context.WithValue(ctx, depsKey{}, d)
+	return d
+}
+
+// dependenciesFromContext returns dependency tracker attached to the context
+func dependenciesFromContext(ctx context.Context) (*dependencyTracker, bool) {
+	d, ok := ctx.Value(depsKey{}).(*dependencyTracker)
+	return d, ok
+}
+
+func (d *dependencyTracker) node(taskName string) *dependencyNode {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	n, ok := d.nodes[taskName]
+	if !ok {
+		n = &dependencyNode{}
+		d.nodes[taskName] = n
+	}
+
+	return n
+}
+
+// require runs specified tasks in parallel and waits until all of them complete.
+//
+// Dependency graph should be checked for cycles before call.
+func (d *dependencyTracker) require(taskNames []string) error {
+	if len(taskNames) == 0 {
+		return nil
+	}
+
+	errs := make([]error, len(taskNames))
+	wg := &sync.WaitGroup{}
+	wg.Add(len(taskNames))
+	for i, name := range taskNames {
+		go func(i int, name string) {
+			defer wg.Done()
+			errs[i] = d.run(name)
+		}(i, name)
+	}
+
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// run starts a task with all its dependencies if it wasn't started before.
+//
+// Concurrent calls wait until first call completes and return the same result.
+func (d *dependencyTracker) run(taskName string) error {
+	n := d.node(taskName)
+	n.once.Do(func() {
+		task, ok := d.runner.manifest.Tasks[taskName]
+		if !ok {
+			n.err = fmt.Errorf("task %q doesn't exists", taskName)
+			return
+		}
+
+		if err := d.require(task.Depends); err != nil {
+			n.err = err
+			return
+		}
+
+		n.err = d.runner.runTask(d.ctx, taskName, task, d.vars)
+	})
+
+	return n.err
+}
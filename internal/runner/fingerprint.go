@@ -0,0 +1,270 @@
+package runner
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-gilbert/gilbert/internal/manifest"
+	"github.com/go-gilbert/gilbert/internal/scope"
+	"github.com/go-gilbert/gilbert/internal/storage"
+)
+
+// machineVars is a list of built-in variables which depend on a machine.
+//
+// They are not used in a job digest to share job outputs between machines.
+var machineVars = map[string]bool{
+	"PROJECT": true,
+	"BUILD":   true,
+	"GOPATH":  true,
+}
+
+// fingerprint is a state of incremental job.
+//
+// Fingerprint file name identifies the job by its definition (action, params, variables, inputs and outputs)
+// and file contains a hash of input and output files contents.
+type fingerprint struct {
+	// key is a digest of job definition
+	key     string
+	file    string
+	baseDir string
+	inputs  []string
+	outputs []string
+}
+
+// newFingerprint creates fingerprint for a job with declared inputs
+func newFingerprint(j manifest.Job, s *scope.Scope) (*fingerprint, error) {
+	projectDir := s.Environment().ProjectDirectory
+	inputs, err := expandPaths(s, projectDir, j.Inputs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid job inputs: %w", err)
+	}
+
+	outputs, err := expandPaths(s, projectDir, j.Outputs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid job outputs: %w", err)
+	}
+
+	// params are resolved, so the job runs again if a command result (e.g. git commit) is changed
+	params, err := s.ExpandParams(j.Params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve job params: %w", err)
+	}
+
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "action=%s\nmixin=%s\ntask=%s\n", j.ActionName, j.MixinName, j.TaskName)
+	_, _ = fmt.Fprintf(h, "params=%s\n", machineIndependent(projectDir, fmt.Sprint(map[string]interface{}(params))))
+
+	// variables are not evaluated, as job might not use them and some of them may run commands
+	writeVars(h, projectDir, s.Globals.Append(s.Variables))
+	_, _ = fmt.Fprintf(h, "inputs=%q\noutputs=%q\n", relPaths(projectDir, inputs), relPaths(projectDir, outputs))
+	key := hex.EncodeToString(h.Sum(nil))
+
+	// job state is specific to the project location
+	fileName := sha256.Sum256([]byte(key + projectDir))
+	file, err := storage.ProjectPath(projectDir, storage.Fingerprints, hex.EncodeToString(fileName[:]))
+	if err != nil {
+		return nil, err
+	}
+
+	return &fingerprint{
+		key:     key,
+		file:    file,
+		baseDir: projectDir,
+		inputs:  inputs,
+		outputs: outputs,
+	}, nil
+}
+
+// inputsDigest returns digest of job definition and contents of input files.
+//
+// Used as a key to store job outputs in a cache, so it doesn't depend on project location.
+func (f *fingerprint) inputsDigest() (string, error) {
+	h := sha256.New()
+	_, _ = io.WriteString(h, f.key)
+	for _, pattern := range f.inputs {
+		if err := hashFiles(h, f.baseDir, pattern); err != nil {
+			return "", err
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// upToDate checks if inputs and outputs were not changed since the last save
+func (f *fingerprint) upToDate() (bool, error) {
+	saved, err := os.ReadFile(f.file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	// output might be a glob pattern, at least one file should match it
+	for _, output := range f.outputs {
+		matches, err := filepath.Glob(output)
+		if err != nil || len(matches) == 0 {
+			return false, err
+		}
+	}
+
+	state, err := f.state()
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(saved, state), nil
+}
+
+// save stores current state of inputs and outputs
+func (f *fingerprint) save() error {
+	state, err := f.state()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(f.file), 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(f.file, state, 0644)
+}
+
+func (f *fingerprint) state() ([]byte, error) {
+	h := sha256.New()
+	for _, group := range [][]string{f.inputs, f.outputs} {
+		for _, pattern := range group {
+			if err := hashFiles(h, f.baseDir, pattern); err != nil {
+				return nil, err
+			}
+		}
+
+		_, _ = io.WriteString(h, "\n")
+	}
+
+	return []byte(hex.EncodeToString(h.Sum(nil))), nil
+}
+
+// expandPaths expands variables in paths and makes them absolute
+func expandPaths(s *scope.Scope, baseDir string, paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	out := make([]string, 0, len(paths))
+	for _, p := range paths {
+		expanded, err := s.ExpandVariables(p)
+		if err != nil {
+			return nil, err
+		}
+
+		if !filepath.IsAbs(expanded) {
+			expanded = filepath.Join(baseDir, expanded)
+		}
+
+		if _, err := filepath.Match(expanded, ""); err != nil {
+			return nil, fmt.Errorf("%w: %q", err, p)
+		}
+
+		out = append(out, expanded)
+	}
+
+	return out, nil
+}
+
+// relPaths returns paths relative to base directory
+func relPaths(baseDir string, paths []string) []string {
+	out := make([]string, 0, len(paths))
+	for _, p := range paths {
+		out = append(out, relPath(baseDir, p))
+	}
+
+	return out
+}
+
+func relPath(baseDir, p string) string {
+	rel, err := filepath.Rel(baseDir, p)
+	if err != nil {
+		return p
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+// hashFiles writes path and contents of all files matched by glob pattern.
+//
+// Matched directories are hashed recursively.
+// File paths are written relative to base directory.
+func hashFiles(h hash.Hash, baseDir, pattern string) error {
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(h, "%s:%d\n", relPath(baseDir, pattern), len(matches))
+	for _, match := range matches {
+		err := filepath.WalkDir(match, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || !d.Type().IsRegular() {
+				return err
+			}
+
+			return hashFile(h, relPath(baseDir, path), path)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func hashFile(h hash.Hash, name, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+	fh := sha256.New()
+	if _, err := io.Copy(fh, f); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(h, "%s %x\n", name, fh.Sum(nil))
+	return nil
+}
+
+// writeVars writes variable definitions except machine-specific ones
+func writeVars(h hash.Hash, projectDir string, vars manifest.Vars) {
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		if !machineVars[k] {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+	for _, k := range keys {
+		_, _ = fmt.Fprintf(h, "var.%s=%q\n", k, machineIndependent(projectDir, vars[k]))
+	}
+}
+
+// machineIndependent replaces project directory in a value with a placeholder,
+// to share job outputs between different project locations.
+func machineIndependent(projectDir, val string) string {
+	if projectDir == "" {
+		return val
+	}
+
+	return strings.ReplaceAll(val, projectDir, "${PROJECT}")
+}
@@ -0,0 +1,110 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-gilbert/gilbert/internal/manifest"
+	"github.com/go-gilbert/gilbert/internal/manifest/expr"
+	"github.com/go-gilbert/gilbert/internal/scope"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprint(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src", "main.go")
+	out := filepath.Join(dir, "build", "app")
+	require.NoError(t, os.MkdirAll(filepath.Dir(src), 0755))
+	require.NoError(t, os.WriteFile(src, []byte("package main"), 0644))
+
+	j := manifest.Job{
+		ActionName: "build",
+		Inputs:     []string{"src"},
+		Outputs:    []string{"build/app"},
+	}
+	newFp := func(vars manifest.Vars) *fingerprint {
+		s := scope.CreateScope(expr.SpecV2Parser{}, dir, vars)
+		fp, err := newFingerprint(j, s)
+		require.NoError(t, err)
+		return fp
+	}
+
+	fp := newFp(nil)
+	ok, err := fp.upToDate()
+	require.NoError(t, err)
+	require.False(t, ok, "job should run for the first time")
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(out), 0755))
+	require.NoError(t, os.WriteFile(out, []byte("binary"), 0644))
+	require.NoError(t, fp.save())
+	ok, err = newFp(nil).upToDate()
+	require.NoError(t, err)
+	require.True(t, ok, "job should be skipped if nothing was changed")
+
+	ok, err = newFp(manifest.Vars{"foo": "bar"}).upToDate()
+	require.NoError(t, err)
+	require.False(t, ok, "job should run if variables were changed")
+
+	require.NoError(t, os.WriteFile(src, []byte("package main\n"), 0644))
+	ok, err = newFp(nil).upToDate()
+	require.NoError(t, err)
+	require.False(t, ok, "job should run if inputs were changed")
+
+	require.NoError(t, fp.save())
+	require.NoError(t, os.Remove(out))
+	ok, err = newFp(nil).upToDate()
+	require.NoError(t, err)
+	require.False(t, ok, "job should run if outputs are missing")
+
+	j.Inputs = []string{"[-]"}
+	_, err = newFingerprint(j, scope.CreateScope(expr.SpecV2Parser{}, dir, nil))
+	require.Error(t, err)
+}
+
+func TestFingerprint_ResolvedValues(t *testing.T) {
+	dir := t.TempDir()
+	rev := filepath.Join(dir, "rev.txt")
+	require.NoError(t, os.WriteFile(rev, []byte("a1"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "dist"), 0755))
+
+	j := manifest.Job{
+		ActionName: "build",
+		Params:     manifest.ActionParams{"version": "${version}", "commit": "${commit}"},
+		Vars:       manifest.Vars{"commit": "$(cat rev.txt)", "broken": "$(exit 1)"},
+		Inputs:     []string{"main.go"},
+		Outputs:    []string{"dist/*"},
+	}
+	newFp := func(version string) *fingerprint {
+		s := scope.CreateScope(expr.SpecV2Parser{}, dir, j.Vars).
+			AppendGlobals(manifest.Vars{"version": version})
+		fp, err := newFingerprint(j, s)
+		require.NoError(t, err)
+		return fp
+	}
+
+	require.NoError(t, newFp("1.0").save())
+	ok, err := newFp("1.0").upToDate()
+	require.NoError(t, err)
+	require.False(t, ok, "job should run if glob outputs are missing")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "dist", "app"), []byte("binary"), 0644))
+	require.NoError(t, newFp("1.0").save())
+	ok, err = newFp("1.0").upToDate()
+	require.NoError(t, err)
+	require.True(t, ok, "job should be skipped if glob outputs exist")
+
+	ok, err = newFp("1.1").upToDate()
+	require.NoError(t, err)
+	require.False(t, ok, "job should run if resolved values were changed")
+
+	require.NoError(t, os.WriteFile(rev, []byte("b2"), 0644))
+	ok, err = newFp("1.0").upToDate()
+	require.NoError(t, err)
+	require.False(t, ok, "job should run if command result was changed")
+
+	j.Params = manifest.ActionParams{"broken": "${broken}"}
+	_, err = newFingerprint(j, scope.CreateScope(expr.SpecV2Parser{}, dir, j.Vars))
+	require.Error(t, err, "referenced variable should be resolved")
+}
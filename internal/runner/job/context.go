@@ -27,6 +27,10 @@ type RunContext struct { // nolint: maligned
 	cancelFn context.CancelFunc
 	once     sync.Once
 
+	// mtx guards holdsSlot
+	mtx       sync.RWMutex
+	holdsSlot bool
+
 	// RootVars used to hold variables of root context
 	RootVars manifest.Vars
 }
@@ -68,6 +72,8 @@ func (r *RunContext) ForkContext() *RunContext {
 		cancelFn: r.cancelFn,
 		child:    true,
 		wg:       r.wg,
+
+		holdsSlot: r.HoldsSlot(),
 	}
 }
 
@@ -83,7 +89,14 @@ func (r *RunContext) SetVars(newVars manifest.Vars) {
 
 // ChildContext creates a new child context with separate Error channel and context
 func (r *RunContext) ChildContext() *RunContext {
-	ctx, cancelFn := context.WithCancel(r.context)
+	return r.ChildContextWith(r.context)
+}
+
+// ChildContextWith does the same as ChildContext but derives context from specified parent.
+//
+// Used to bind a group of jobs to a shared context.
+func (r *RunContext) ChildContextWith(parent context.Context) *RunContext {
+	ctx, cancelFn := context.WithCancel(parent)
 
 	return &RunContext{
 		RootVars: r.RootVars,
@@ -92,6 +105,8 @@ func (r *RunContext) ChildContext() *RunContext {
 		Error:    make(chan error, 1),
 		cancelFn: cancelFn,
 		child:    true,
+
+		holdsSlot: r.HoldsSlot(),
 	}
 }
 
@@ -140,6 +155,22 @@ func (r *RunContext) Context() context.Context {
 	return r.context
 }
 
+// HoldSlot marks that job acquired a slot of parallel jobs limit.
+//
+// Mark is inherited by child and forked contexts created after this call.
+func (r *RunContext) HoldSlot() {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.holdsSlot = true
+}
+
+// HoldsSlot checks if job or its parent acquired a slot of parallel jobs limit
+func (r *RunContext) HoldsSlot() bool {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	return r.holdsSlot
+}
+
 // Result reports job result and finished the context
 func (r *RunContext) Result(err error) {
 	r.once.Do(func() {
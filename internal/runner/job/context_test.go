@@ -15,14 +15,14 @@ import (
 
 func TestRunContext_Result(t *testing.T) {
 	rtx := NewRunContext(context.Background(), nil, &test.Log{T: t})
-	errs := make(chan error)
+	errs := make(chan error, 1)
 	expected := errors.New("test error")
 	wg := &sync.WaitGroup{}
 	rtx.SetErrorChannel(errs)
 	rtx.SetWaitGroup(wg)
 	assert.Equal(t, true, rtx.IsAlive())
+	wg.Add(1)
 	go func(e error) {
-		wg.Add(1) //nolint:staticcheck
 		rtx.Result(e)
 	}(expected)
 
@@ -35,8 +35,8 @@ func TestRunContext_Success(t *testing.T) {
 	rtx := NewRunContext(context.Background(), nil, &test.Log{T: t})
 	wg := &sync.WaitGroup{}
 	rtx.SetWaitGroup(wg)
+	wg.Add(1)
 	go func() {
-		wg.Add(1) //nolint:staticcheck
 		time.Sleep(time.Millisecond * 100)
 		rtx.Success()
 	}()
@@ -2,10 +2,12 @@ package runner
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/go-gilbert/gilbert/internal/cache"
 	"github.com/go-gilbert/gilbert/internal/log"
 	"github.com/go-gilbert/gilbert/internal/manifest"
 	"github.com/go-gilbert/gilbert/internal/runner/job"
@@ -13,6 +15,9 @@ import (
 	"github.com/go-gilbert/gilbert/internal/support/shell"
 )
 
+// commandsKey is context key of shell expressions cache of current task runner invocation
+type commandsKey struct{}
+
 var errNoTaskHandler = fmt.Errorf("no task handler defined, please define task handler in 'plugin' or 'mixin' paramerer")
 
 type Config struct {
@@ -20,6 +25,17 @@ type Config struct {
 	Handlers HandlerResolver
 	Manifest *manifest.Manifest
 	WorkDir  string
+
+	// MaxParallel limits count of actions running at the same time.
+	//
+	// Zero means no limit.
+	MaxParallel int
+
+	// Cache is optional job outputs cache.
+	//
+	// Outputs of jobs with declared inputs are stored in the cache
+	// and restored instead of job execution when inputs are the same.
+	Cache *cache.Cache
 }
 
 // TaskRunner runs tasks
@@ -30,21 +46,23 @@ type TaskRunner struct {
 	handlerResolver HandlerResolver
 	context         context.Context
 	cancelFn        context.CancelFunc
+	scheduler       *scheduler
+	cache           *cache.Cache
 
 	CurrentDirectory string
 }
 
 // NewTaskRunner creates a new task runner instance
 func NewTaskRunner(cfg Config) *TaskRunner {
-	t := &TaskRunner{
+	return &TaskRunner{
 		manifest:         cfg.Manifest,
 		CurrentDirectory: cfg.WorkDir,
 		log:              cfg.Logger,
 		subLogger:        cfg.Logger.SubLogger(),
 		handlerResolver:  cfg.Handlers,
+		scheduler:        newScheduler(cfg.MaxParallel),
+		cache:            cfg.Cache,
 	}
-
-	return t
 }
 
 // SetContext sets execution context
@@ -53,6 +71,24 @@ func (t *TaskRunner) SetContext(ctx context.Context, fn context.CancelFunc) {
 	t.cancelFn = fn
 }
 
+// WithCommandCache returns a copy of context with a new cache of shell expression results.
+//
+// Results are shared only between jobs started with returned context.
+// Context is returned as is if commands caching is disabled in manifest.
+func (t *TaskRunner) WithCommandCache(ctx context.Context) context.Context {
+	if t.manifest == nil || !t.manifest.CacheCommands() {
+		return ctx
+	}
+
+	return context.WithValue(ctx, commandsKey{}, scope.NewCommandCache())
+}
+
+// commandCacheFromContext returns shell expressions cache attached to the context
+func commandCacheFromContext(ctx context.Context) *scope.CommandCache {
+	c, _ := ctx.Value(commandsKey{}).(*scope.CommandCache)
+	return c
+}
+
 // ActionByName returns action handler constructor
 func (t *TaskRunner) ActionByName(actionName string) (HandlerFactory, error) {
 	return t.handlerResolver.GetHandler(actionName)
@@ -67,6 +103,10 @@ func (t *TaskRunner) Stop() {
 
 // Run executes task by name.
 //
+// Tasks listed in task's "depends" section are started before the task.
+// Independent dependencies run in parallel and each of them runs only once.
+// Results of shell expressions are reused only within the same invocation.
+//
 // "vars" parameter is optional and allows to override job scope values.
 func (t *TaskRunner) Run(taskName string, vars manifest.Vars) (err error) {
 	task, ok := t.manifest.Tasks[taskName]
@@ -74,38 +114,49 @@ func (t *TaskRunner) Run(taskName string, vars manifest.Vars) (err error) {
 		return fmt.Errorf("task %q doesn't exists", taskName)
 	}
 
-	t.log.Logf("Running task %q...", taskName)
-	steps := len(task)
+	if err := t.manifest.Tasks.CheckDependencies(taskName); err != nil {
+		return err
+	}
 
-	sl := t.subLogger.SubLogger()
 	if t.context == nil {
 		t.log.Warn("Warning: task context was not set")
 		t.context, t.cancelFn = context.WithCancel(context.Background())
 	}
 
+	deps := newDependencyTracker(t.WithCommandCache(t.context), t, vars)
+	if err := deps.require(task.Depends); err != nil {
+		return err
+	}
+
+	return t.runTask(deps.ctx, taskName, task, vars)
+}
+
+// runTask runs task jobs without task dependencies
+func (t *TaskRunner) runTask(ctx context.Context, taskName string, task manifest.Task, vars manifest.Vars) (err error) {
+	t.log.Logf("Running task %q...", taskName)
+	task = task.ExpandMatrix()
+	steps := len(task.Jobs)
+
+	sl := t.subLogger.SubLogger()
+
 	// Set waitgroup and buff channel for async jobs.
 	var tracker *asyncJobTracker
 	asyncJobsCount := task.AsyncJobsCount()
 	if asyncJobsCount > 0 {
 		t.subLogger.Debugf("runner: %d async jobs in task", asyncJobsCount)
-		tracker = newAsyncJobTracker(t.context, t.subLogger, asyncJobsCount)
-		go tracker.trackAsyncJobs()
+		tracker = newAsyncJobTracker(ctx, t.subLogger, task.OnAsyncFailure, task.MaxParallel)
 
 		defer func() {
 			// Wait for unfinished async tasks
 			// and collect results from async jobs
 			t.subLogger.Logf("Waiting for %d async job(s) to complete", asyncJobsCount)
 			if asyncErr := tracker.wait(); asyncErr != nil {
-				if err == nil {
-					// Report error only if no previous errors.
-					// P.S - it's okay since all async errors were logged previously
-					err = fmt.Errorf("task %q returned error in async job: %s", taskName, asyncErr)
-				}
+				err = errors.Join(err, fmt.Errorf("task %q returned error in async job: %w", taskName, asyncErr))
 			}
 		}()
 	}
 
-	for jobIndex, j := range task {
+	for jobIndex, j := range task.Jobs {
 		currentStep := jobIndex + 1
 		descr := j.FormatDescription()
 		if steps > 1 {
@@ -115,15 +166,17 @@ func (t *TaskRunner) Run(taskName string, vars manifest.Vars) (err error) {
 			t.subLogger.Infof("- %s", descr)
 		}
 		var err error
-		ctx := job.NewRunContext(t.context, vars, sl)
-
 		if j.Async {
-			tracker.decorateJobContext(ctx)
-			go t.handleJob(j, ctx)
+			rtx := job.NewRunContext(tracker.context(), vars, sl)
+			tracker.start(rtx, currentStep, descr, func() {
+				t.handleJob(j, rtx)
+			})
 			continue
 		}
 
-		if err = t.startJobAndWait(j, ctx); err != nil {
+		rtx := job.NewRunContext(ctx, vars, sl)
+
+		if err = t.startJobAndWait(j, rtx); err != nil {
 			return fmt.Errorf("task %q returned an error on step %d: %v", taskName, currentStep, err)
 		}
 	}
@@ -140,6 +193,23 @@ func (t *TaskRunner) RunTask(taskName string, ctx *job.RunContext, scope *scope.
 		return fmt.Errorf("task %q doesn't exists", taskName)
 	}
 
+	if task.HasDependencies() {
+		if err := t.manifest.Tasks.CheckDependencies(taskName); err != nil {
+			return err
+		}
+
+		// Prerequisites are shared with other tasks and use root variables.
+		ctx.Log().Debugf("runner: start dependencies of sub-task %q", taskName)
+		deps, ok := dependenciesFromContext(ctx.Context())
+		if !ok {
+			deps = newDependencyTracker(ctx.Context(), t, ctx.Vars())
+		}
+
+		if err := deps.require(task.Depends); err != nil {
+			return err
+		}
+	}
+
 	// Create a task copy with injected local variables from scope
 	// if scope has some variables
 	locals := scope.Vars()
@@ -180,11 +250,41 @@ func (t *TaskRunner) startJobAndWait(job manifest.Job, ctx *job.RunContext) erro
 	return err
 }
 
+// varSources returns descriptions of places where job scope variables were defined.
+//
+// Job variables override manifest variables and "--var" values override both.
+func (t *TaskRunner) varSources(j manifest.Job, vars manifest.Vars) map[string]string {
+	out := make(map[string]string, len(t.manifest.Vars)+len(j.Vars)+len(vars))
+	for k := range t.manifest.Vars {
+		src, ok := t.manifest.VarSource(k)
+		switch {
+		case !ok:
+			continue
+		case src == t.manifest.Location():
+			out[k] = fmt.Sprintf("manifest file %q", src)
+		default:
+			out[k] = fmt.Sprintf("imported file %q", src)
+		}
+	}
+
+	for k := range j.Vars {
+		out[k] = fmt.Sprintf("job %q", j.FormatDescription())
+	}
+
+	for k := range vars {
+		out[k] = "--var flag"
+	}
+
+	return out
+}
+
 // handleJob handles specified job
 func (t *TaskRunner) handleJob(j manifest.Job, ctx *job.RunContext) {
 	s := scope.CreateScope(t.manifest.Parser, t.CurrentDirectory, j.Vars).
 		AppendGlobals(t.manifest.Vars).
-		AppendVariables(ctx.Vars())
+		AppendVariables(ctx.Vars()).
+		UseCommandCache(commandCacheFromContext(ctx.Context())).
+		AddVarSources(t.varSources(j, ctx.Vars()))
 
 	// check if job should be run
 	if !t.shouldRunJob(j, s) {
@@ -193,6 +293,33 @@ func (t *TaskRunner) handleJob(j manifest.Job, ctx *job.RunContext) {
 		return
 	}
 
+	// check if job inputs were changed since the last run
+	var (
+		fp  *fingerprint
+		err error
+	)
+	if j.IsIncremental() {
+		if fp, err = newFingerprint(j, s); err != nil {
+			ctx.Log().Warnf("Failed to read job fingerprint, job will be started without cache: %s", err)
+		}
+	}
+
+	if fp != nil {
+		if ok, err := fp.upToDate(); err != nil {
+			ctx.Log().Warnf("Failed to check job inputs, job will be started: %s", err)
+		} else if ok {
+			ctx.Log().Info("step was skipped, inputs and outputs are unchanged")
+			ctx.Success()
+			return
+		}
+
+		if t.restoreOutputs(ctx, fp) {
+			ctx.Log().Info("step was skipped, outputs were restored from cache")
+			ctx.Success()
+			return
+		}
+	}
+
 	// Wait if necessary
 	if j.Delay > 0 {
 		ctx.Log().Debugf("runner: job delay defined, waiting %dms...", j.Delay)
@@ -208,34 +335,97 @@ func (t *TaskRunner) handleJob(j manifest.Job, ctx *job.RunContext) {
 	execType := j.Type()
 	switch execType {
 	case manifest.ExecAction:
-		t.handleActionCall(ctx, j, s)
+		err = t.handleActionCall(ctx, j, s)
 	case manifest.ExecMixin:
-		t.handleMixinCall(ctx, j, s)
+		err = t.handleMixinCall(ctx, j, s)
 	case manifest.ExecTask:
-		t.handleSubTaskCall(ctx, j, s)
+		err = t.handleSubTaskCall(ctx, j, s)
 	default:
-		ctx.Result(errNoTaskHandler)
+		err = errNoTaskHandler
+	}
+
+	if err == nil && fp != nil && ctx.Context().Err() == nil {
+		if serr := fp.save(); serr != nil {
+			ctx.Log().Warnf("Failed to save job fingerprint: %s", serr)
+		}
+
+		t.cacheOutputs(ctx, fp)
 	}
-}
 
-func (t *TaskRunner) handleSubTaskCall(ctx *job.RunContext, j manifest.Job, s *scope.Scope) {
-	err := t.RunTask(j.TaskName, ctx, s)
 	ctx.Result(err)
 }
 
-func (t *TaskRunner) handleActionCall(ctx *job.RunContext, j manifest.Job, s *scope.Scope) {
-	factory, err := t.ActionByName(j.ActionName)
+// restoreOutputs restores job outputs from cache if job with the same inputs was cached before
+func (t *TaskRunner) restoreOutputs(ctx *job.RunContext, fp *fingerprint) bool {
+	if t.cache == nil || len(fp.outputs) == 0 {
+		return false
+	}
+
+	key, err := fp.inputsDigest()
 	if err != nil {
-		ctx.Result(err)
+		ctx.Log().Warnf("Failed to check job inputs: %s", err)
+		return false
+	}
+
+	ok, err := t.cache.Restore(ctx.Context(), key, fp.baseDir)
+	if err != nil {
+		ctx.Log().Warnf("Failed to restore job outputs from cache: %s", err)
+		return false
+	}
+
+	if !ok {
+		ctx.Log().Debugf("runner: job outputs %s not found in cache", key)
+		return false
+	}
+
+	if err := fp.save(); err != nil {
+		ctx.Log().Warnf("Failed to save job fingerprint: %s", err)
+	}
+
+	return true
+}
+
+// cacheOutputs stores job outputs in cache
+func (t *TaskRunner) cacheOutputs(ctx *job.RunContext, fp *fingerprint) {
+	if t.cache == nil || len(fp.outputs) == 0 {
 		return
 	}
 
-	actionHandler, err := factory(s, j.Params)
+	key, err := fp.inputsDigest()
+	if err == nil {
+		err = t.cache.Save(ctx.Context(), key, fp.baseDir, fp.outputs)
+	}
+
 	if err != nil {
-		ctx.Result(fmt.Errorf("failed to create action handler instance of '%s': %s", j.ActionName, err))
+		ctx.Log().Warnf("Failed to save job outputs to cache: %s", err)
 		return
 	}
 
+	ctx.Log().Debugf("runner: job outputs saved to cache as %s", key)
+}
+
+func (t *TaskRunner) handleSubTaskCall(ctx *job.RunContext, j manifest.Job, s *scope.Scope) error {
+	return t.RunTask(j.TaskName, ctx, s)
+}
+
+func (t *TaskRunner) handleActionCall(ctx *job.RunContext, j manifest.Job, s *scope.Scope) error {
+	factory, err := t.ActionByName(j.ActionName)
+	if err != nil {
+		return err
+	}
+
+	actionHandler, err := factory(s, j.Params)
+	if err != nil {
+		return fmt.Errorf("failed to create action handler instance of '%s': %s", j.ActionName, err)
+	}
+
+	// Wait for a free slot if parallel actions limit is set
+	release, err := t.scheduler.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	// Handle stop event
 	// Event may arrive on SIGKILL or when timeout reached
 	go func() {
@@ -245,29 +435,22 @@ func (t *TaskRunner) handleActionCall(ctx *job.RunContext, j manifest.Job, s *sc
 	}()
 
 	// EvalCommand actionHandler and send result
-	err = actionHandler.Call(ctx, t)
-	ctx.Result(err)
+	return actionHandler.Call(ctx, t)
 }
 
 // handleMixinCall constructs a task from job with mixin and runs it
 //
 // requires subLogger instance to create cascade logging output
-func (t *TaskRunner) handleMixinCall(ctx *job.RunContext, j manifest.Job, s *scope.Scope) {
+func (t *TaskRunner) handleMixinCall(ctx *job.RunContext, j manifest.Job, s *scope.Scope) error {
 	mx, ok := t.manifest.Mixins[j.MixinName]
 	if !ok {
-		ctx.Result(fmt.Errorf("mixin %q doesn't exists", j.MixinName))
-		return
+		return fmt.Errorf("mixin %q doesn't exists", j.MixinName)
 	}
 
 	// Create a task from mixin and job params
 	ctx.Log().Debugf("runner: create sub-task from mixin %q", j.MixinName)
 	task := mx.ToTask(s.Vars())
-	if err := t.runSubTask(task, s, ctx); err != nil {
-		ctx.Result(err)
-		return
-	}
-
-	ctx.Success()
+	return t.runSubTask(task, s, ctx)
 }
 
 // runSubTask used to run sub-tasks created by parent job
@@ -277,32 +460,27 @@ func (t *TaskRunner) handleMixinCall(ctx *job.RunContext, j manifest.Job, s *sco
 // subLogger used to create stack of log lines
 func (t *TaskRunner) runSubTask(task manifest.Task, parentScope *scope.Scope, parentCtx *job.RunContext) (err error) {
 	// FIXME: drop copy-paste from Run
-	steps := len(task)
+	task = task.ExpandMatrix()
+	steps := len(task.Jobs)
 
 	// Set waitgroup and buff channel for async jobs.
 	var tracker *asyncJobTracker
 	asyncJobsCount := task.AsyncJobsCount()
 	if asyncJobsCount > 0 {
 		parentCtx.Log().Debugf("runner: %d async jobs in sub-task", asyncJobsCount)
-		tracker = newAsyncJobTracker(parentCtx.Context(), parentCtx.Log(), asyncJobsCount)
-		go tracker.trackAsyncJobs()
+		tracker = newAsyncJobTracker(parentCtx.Context(), parentCtx.Log(), task.OnAsyncFailure, task.MaxParallel)
 
 		defer func() {
 			// Wait for unfinished async tasks
 			// and collect results from async jobs
 			t.subLogger.Logf("Waiting for %d async job(s) to complete", asyncJobsCount)
 			if asyncErr := tracker.wait(); asyncErr != nil {
-
-				if err == nil {
-					// Report error only if no previous errors.
-					// P.S - it's okay since all async errors were logged previously
-					err = fmt.Errorf("async job returned error - %s", asyncErr)
-				}
+				err = errors.Join(err, asyncErr)
 			}
 		}()
 	}
 
-	for jobIndex, j := range task {
+	for jobIndex, j := range task.Jobs {
 		currentStep := jobIndex + 1
 
 		// sub-task label can contain template expressions (e.g. mixin step description)
@@ -321,13 +499,16 @@ func (t *TaskRunner) runSubTask(task manifest.Task, parentScope *scope.Scope, pa
 			parentCtx.Log().Infof("- %s", descr)
 		}
 
-		ctx := parentCtx.ChildContext()
 		if j.Async {
-			tracker.decorateJobContext(ctx)
-			go t.handleJob(j, ctx)
+			ctx := parentCtx.ChildContextWith(tracker.context())
+			tracker.start(ctx, currentStep, descr, func() {
+				t.handleJob(j, ctx)
+			})
 			continue
 		}
 
+		ctx := parentCtx.ChildContext()
+
 		if err = t.startJobAndWait(j, ctx); err != nil {
 			return fmt.Errorf("%s (sub-task step %d)", err, currentStep)
 		}
@@ -337,16 +518,30 @@ func (t *TaskRunner) runSubTask(task manifest.Task, parentScope *scope.Scope, pa
 }
 
 func (t *TaskRunner) shouldRunJob(job manifest.Job, scp *scope.Scope) bool {
-	condCmd := strings.TrimSpace(job.Condition)
+	l := t.subLogger.SubLogger()
+	if cond := strings.TrimSpace(job.Condition); cond != "" {
+		l.Debugf("runner: assert condition: %q", cond)
+		ok, err := scp.EvalCondition(cond)
+		if err != nil {
+			l.Error(err.Error())
+			l.Warn("Failed to evaluate 'if' expression, job will be skipped")
+			return false
+		}
+
+		if !ok {
+			return false
+		}
+	}
+
+	condCmd := strings.TrimSpace(job.ShellCondition)
 	if condCmd == "" {
 		return true
 	}
 
-	l := t.subLogger.SubLogger()
 	condCmd, err := scp.ExpandVariables(condCmd)
 	if err != nil {
 		l.Error(err.Error())
-		l.Warn("Failed to parse value inside 'if' expression, job will be skipped")
+		l.Warn("Failed to parse value inside 'ifShell' expression, job will be skipped")
 		return false
 	}
 	cmd := shell.PrepareCommand(condCmd)
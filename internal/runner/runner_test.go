@@ -2,9 +2,13 @@ package runner
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/go-gilbert/gilbert/internal/cache"
 	"github.com/go-gilbert/gilbert/internal/manifest"
 	"github.com/go-gilbert/gilbert/internal/manifest/expr"
 	"github.com/go-gilbert/gilbert/internal/runner/job"
@@ -20,18 +24,45 @@ type results struct {
 	startTime  time.Time
 	endTime    time.Time
 	cancelTime time.Time
+
+	mtx       sync.Mutex
+	calls     []string
+	active    int
+	maxActive int
+}
+
+func (r *results) addCall(name string) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.calls = append(r.calls, name)
+}
+
+func (r *results) begin() {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.active++
+	if r.active > r.maxActive {
+		r.maxActive = r.active
+	}
+}
+
+func (r *results) end() {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.active--
 }
 
 func TestTaskRunner_Run(t *testing.T) {
 	defaultParser := expr.SpecV2Parser{}
 
 	cases := map[string]struct {
-		skip     bool
-		taskName string
-		m        manifest.Manifest
-		err      string
-		before   func(t *testing.T, tr *TaskRunner, hs *HandlerSet, r *results)
-		after    func(t *testing.T, tr *TaskRunner, l *test.Log, r *results)
+		skip        bool
+		taskName    string
+		maxParallel int
+		m           manifest.Manifest
+		err         string
+		before      func(t *testing.T, tr *TaskRunner, hs *HandlerSet, r *results)
+		after       func(t *testing.T, tr *TaskRunner, l *test.Log, r *results)
 	}{
 		"error if task not exists": {
 			taskName: "foo",
@@ -40,12 +71,12 @@ func TestTaskRunner_Run(t *testing.T) {
 		"error if job is empty": {
 			taskName: "foo",
 			err:      "no task handler defined",
-			m:        manifest.Manifest{Tasks: manifest.TaskSet{"foo": manifest.Task{manifest.Job{}}}},
+			m:        manifest.Manifest{Tasks: manifest.TaskSet{"foo": manifest.Task{Jobs: []manifest.Job{manifest.Job{}}}}},
 		},
 		"error if action not exists": {
 			taskName: "foo",
 			err:      `no such action handler: "foo"`,
-			m:        manifest.Manifest{Tasks: manifest.TaskSet{"foo": manifest.Task{manifest.Job{ActionName: "foo"}}}},
+			m:        manifest.Manifest{Tasks: manifest.TaskSet{"foo": manifest.Task{Jobs: []manifest.Job{manifest.Job{ActionName: "foo"}}}}},
 			after: func(t *testing.T, tr *TaskRunner, l *test.Log, r *results) {
 				l.AssertMessage("task context was not set")
 			},
@@ -53,10 +84,10 @@ func TestTaskRunner_Run(t *testing.T) {
 		"error if action returned error": {
 			taskName: "foo",
 			err:      "fail",
-			m: manifest.Manifest{Tasks: manifest.TaskSet{"foo": manifest.Task{
+			m: manifest.Manifest{Tasks: manifest.TaskSet{"foo": manifest.Task{Jobs: []manifest.Job{
 				manifest.Job{ActionName: testAction, Params: manifest.ActionParams{"err": "fail"}, Async: true},
 				manifest.Job{ActionName: testAction, Params: manifest.ActionParams{"err": "fail"}},
-			}}},
+			}}}},
 		},
 		"error if action factory returned error": {
 			taskName: "foo",
@@ -66,15 +97,15 @@ func TestTaskRunner_Run(t *testing.T) {
 					return nil, errors.New("foo")
 				})
 			},
-			m: manifest.Manifest{Tasks: manifest.TaskSet{"foo": manifest.Task{
+			m: manifest.Manifest{Tasks: manifest.TaskSet{"foo": manifest.Task{Jobs: []manifest.Job{
 				manifest.Job{ActionName: "testBadAction"},
-			}}},
+			}}}},
 		},
 		"wait until async task complete": {
 			taskName: "foo",
-			m: manifest.Manifest{Tasks: manifest.TaskSet{"foo": manifest.Task{
+			m: manifest.Manifest{Tasks: manifest.TaskSet{"foo": manifest.Task{Jobs: []manifest.Job{
 				manifest.Job{ActionName: "testAsync", Async: true},
-			}}},
+			}}}},
 			before: func(t *testing.T, _ *TaskRunner, hs *HandlerSet, r *results) {
 				_ = hs.HandleFunc("testAsync", func(*scope.Scope, manifest.ActionParams) (ActionHandler, error) {
 					return &asyncTestHandle{data: r}, nil
@@ -86,9 +117,9 @@ func TestTaskRunner_Run(t *testing.T) {
 		},
 		"respect exec condition": {
 			taskName: "foo",
-			m: manifest.Manifest{Tasks: manifest.TaskSet{"foo": manifest.Task{
-				manifest.Job{ActionName: "testTimeout", Condition: "badcommand"},
-			}}},
+			m: manifest.Manifest{Tasks: manifest.TaskSet{"foo": manifest.Task{Jobs: []manifest.Job{
+				manifest.Job{ActionName: "testTimeout", ShellCondition: "badcommand"},
+			}}}},
 			before: func(t *testing.T, _ *TaskRunner, hs *HandlerSet, r *results) {
 				_ = hs.HandleFunc("testTimeout", func(*scope.Scope, manifest.ActionParams) (ActionHandler, error) {
 					return &asyncTestHandle{data: r}, nil
@@ -100,9 +131,9 @@ func TestTaskRunner_Run(t *testing.T) {
 		},
 		"skip job if condition expression is bad": {
 			taskName: "foo",
-			m: manifest.Manifest{Tasks: manifest.TaskSet{"foo": manifest.Task{
-				manifest.Job{ActionName: "testBadConditionHook", Condition: "${bad} condition"},
-			}}},
+			m: manifest.Manifest{Tasks: manifest.TaskSet{"foo": manifest.Task{Jobs: []manifest.Job{
+				manifest.Job{ActionName: "testBadConditionHook", ShellCondition: "${bad} condition"},
+			}}}},
 			before: func(t *testing.T, _ *TaskRunner, hs *HandlerSet, r *results) {
 				_ = hs.HandleFunc("testBadConditionHook", func(*scope.Scope, manifest.ActionParams) (ActionHandler, error) {
 					return &asyncTestHandle{data: r}, nil
@@ -114,9 +145,9 @@ func TestTaskRunner_Run(t *testing.T) {
 		},
 		"run job if expression returns OK result": {
 			taskName: "foo",
-			m: manifest.Manifest{Tasks: manifest.TaskSet{"foo": manifest.Task{
-				manifest.Job{ActionName: "testOKConditionHook", Condition: "echo ${msg}", Vars: manifest.Vars{"msg": "hello"}},
-			}}},
+			m: manifest.Manifest{Tasks: manifest.TaskSet{"foo": manifest.Task{Jobs: []manifest.Job{
+				manifest.Job{ActionName: "testOKConditionHook", ShellCondition: "echo ${msg}", Vars: manifest.Vars{"msg": "hello"}},
+			}}}},
 			before: func(t *testing.T, _ *TaskRunner, hs *HandlerSet, r *results) {
 				_ = hs.HandleFunc("testOKConditionHook", func(*scope.Scope, manifest.ActionParams) (ActionHandler, error) {
 					return &asyncTestHandle{data: r}, nil
@@ -126,11 +157,53 @@ func TestTaskRunner_Run(t *testing.T) {
 				assert.Truef(t, r.done, "task was not started")
 			},
 		},
+		"run job if condition expression is true": {
+			taskName: "foo",
+			m: manifest.Manifest{Tasks: manifest.TaskSet{"foo": manifest.Task{Jobs: []manifest.Job{
+				manifest.Job{ActionName: "testExprCondition", Condition: `vars.release == "true" && os != ""`, Vars: manifest.Vars{"release": "true"}},
+			}}}},
+			before: func(t *testing.T, _ *TaskRunner, hs *HandlerSet, r *results) {
+				_ = hs.HandleFunc("testExprCondition", func(*scope.Scope, manifest.ActionParams) (ActionHandler, error) {
+					return &asyncTestHandle{data: r}, nil
+				})
+			},
+			after: func(t *testing.T, _ *TaskRunner, _ *test.Log, r *results) {
+				assert.Truef(t, r.done, "task was not started")
+			},
+		},
+		"skip job if condition expression is false": {
+			taskName: "foo",
+			m: manifest.Manifest{Tasks: manifest.TaskSet{"foo": manifest.Task{Jobs: []manifest.Job{
+				manifest.Job{ActionName: "testExprCondition", Condition: `vars.release == "true"`, Vars: manifest.Vars{"release": "false"}},
+			}}}},
+			before: func(t *testing.T, _ *TaskRunner, hs *HandlerSet, r *results) {
+				_ = hs.HandleFunc("testExprCondition", func(*scope.Scope, manifest.ActionParams) (ActionHandler, error) {
+					return &asyncTestHandle{data: r}, nil
+				})
+			},
+			after: func(t *testing.T, _ *TaskRunner, _ *test.Log, r *results) {
+				assert.Falsef(t, r.done, "task shouldn't start")
+			},
+		},
+		"skip job if condition expression is invalid": {
+			taskName: "foo",
+			m: manifest.Manifest{Tasks: manifest.TaskSet{"foo": manifest.Task{Jobs: []manifest.Job{
+				manifest.Job{ActionName: "testExprCondition", Condition: `unknown == 1`},
+			}}}},
+			before: func(t *testing.T, _ *TaskRunner, hs *HandlerSet, r *results) {
+				_ = hs.HandleFunc("testExprCondition", func(*scope.Scope, manifest.ActionParams) (ActionHandler, error) {
+					return &asyncTestHandle{data: r}, nil
+				})
+			},
+			after: func(t *testing.T, _ *TaskRunner, _ *test.Log, r *results) {
+				assert.Falsef(t, r.done, "task shouldn't start")
+			},
+		},
 		"respect timeout": {
 			taskName: "foo",
-			m: manifest.Manifest{Tasks: manifest.TaskSet{"foo": manifest.Task{
+			m: manifest.Manifest{Tasks: manifest.TaskSet{"foo": manifest.Task{Jobs: []manifest.Job{
 				manifest.Job{ActionName: "testTimeout", Delay: manifest.Period(800)},
-			}}},
+			}}}},
 			before: func(t *testing.T, _ *TaskRunner, hs *HandlerSet, r *results) {
 				_ = hs.HandleFunc("testTimeout", func(*scope.Scope, manifest.ActionParams) (ActionHandler, error) {
 					return &asyncTestHandle{data: r}, nil
@@ -148,9 +221,9 @@ func TestTaskRunner_Run(t *testing.T) {
 		},
 		"respect deadline": {
 			taskName: "foo",
-			m: manifest.Manifest{Tasks: manifest.TaskSet{"foo": manifest.Task{
+			m: manifest.Manifest{Tasks: manifest.TaskSet{"foo": manifest.Task{Jobs: []manifest.Job{
 				manifest.Job{ActionName: "testDeadline", Deadline: manifest.Period(10)},
-			}}},
+			}}}},
 			before: func(t *testing.T, _ *TaskRunner, hs *HandlerSet, r *results) {
 				_ = hs.HandleFunc("testDeadline", func(*scope.Scope, manifest.ActionParams) (ActionHandler, error) {
 					return &asyncTestHandle{data: r}, nil
@@ -175,10 +248,10 @@ func TestTaskRunner_Run(t *testing.T) {
 					},
 				},
 				Tasks: manifest.TaskSet{
-					"foo": manifest.Task{
+					"foo": manifest.Task{Jobs: []manifest.Job{
 						manifest.Job{ActionName: testAction},
 						manifest.Job{MixinName: "mx1", Vars: manifest.Vars{"foo": "bar"}},
-					},
+					}},
 				},
 			},
 			before: func(t *testing.T, _ *TaskRunner, hs *HandlerSet, r *results) {
@@ -202,10 +275,10 @@ func TestTaskRunner_Run(t *testing.T) {
 					},
 				},
 				Tasks: manifest.TaskSet{
-					"foo": manifest.Task{
+					"foo": manifest.Task{Jobs: []manifest.Job{
 						manifest.Job{ActionName: testAction},
 						manifest.Job{MixinName: "mx1"},
-					},
+					}},
 				},
 			},
 		},
@@ -214,10 +287,10 @@ func TestTaskRunner_Run(t *testing.T) {
 			err:      `mixin "mx1" doesn't exists`,
 			m: manifest.Manifest{
 				Tasks: manifest.TaskSet{
-					"foo": manifest.Task{
+					"foo": manifest.Task{Jobs: []manifest.Job{
 						manifest.Job{ActionName: testAction},
 						manifest.Job{MixinName: "mx1"},
-					},
+					}},
 				},
 			},
 		},
@@ -230,9 +303,9 @@ func TestTaskRunner_Run(t *testing.T) {
 					},
 				},
 				Tasks: manifest.TaskSet{
-					"foo": manifest.Task{
+					"foo": manifest.Task{Jobs: []manifest.Job{
 						manifest.Job{MixinName: "mx1"},
-					},
+					}},
 				},
 			},
 		},
@@ -241,9 +314,9 @@ func TestTaskRunner_Run(t *testing.T) {
 			err:      `task "t2" doesn't exists`,
 			m: manifest.Manifest{
 				Tasks: manifest.TaskSet{
-					"t1": manifest.Task{
+					"t1": manifest.Task{Jobs: []manifest.Job{
 						manifest.Job{TaskName: "t2"},
-					},
+					}},
 				},
 			},
 		},
@@ -251,14 +324,14 @@ func TestTaskRunner_Run(t *testing.T) {
 			taskName: "foo",
 			m: manifest.Manifest{
 				Tasks: manifest.TaskSet{
-					"foo": manifest.Task{
+					"foo": manifest.Task{Jobs: []manifest.Job{
 						manifest.Job{ActionName: testAction},
 						manifest.Job{TaskName: "bar", Vars: manifest.Vars{"foo": "bar"}},
-					},
-					"bar": manifest.Task{
+					}},
+					"bar": manifest.Task{Jobs: []manifest.Job{
 						manifest.Job{ActionName: testAction, Async: true},
 						manifest.Job{Description: "start ${foo}", ActionName: "testSubTaskExec1"},
-					},
+					}},
 				},
 			},
 			before: func(t *testing.T, _ *TaskRunner, hs *HandlerSet, r *results) {
@@ -277,15 +350,296 @@ func TestTaskRunner_Run(t *testing.T) {
 			err:      `task "foo" returned an error on step 2: fail (sub-task step 1)`,
 			m: manifest.Manifest{
 				Tasks: manifest.TaskSet{
-					"foo": manifest.Task{
+					"foo": manifest.Task{Jobs: []manifest.Job{
 						manifest.Job{ActionName: testAction},
 						manifest.Job{TaskName: "bar"},
+					}},
+					"bar": manifest.Task{Jobs: []manifest.Job{
+						manifest.Job{ActionName: testAction, Params: manifest.ActionParams{"err": "fail"}},
+					}},
+				},
+			},
+		},
+		"report errors of all async jobs": {
+			taskName: "foo",
+			err:      "async job 1 (first): fail1\nasync job 3 (third): fail3",
+			m: manifest.Manifest{Tasks: manifest.TaskSet{"foo": manifest.Task{Jobs: []manifest.Job{
+				{Description: "first", ActionName: testAction, Params: manifest.ActionParams{"err": "fail1"}, Async: true},
+				{Description: "second", ActionName: testAction, Async: true},
+				{Description: "third", ActionName: testAction, Params: manifest.ActionParams{"err": "fail3"}, Async: true},
+			}}}},
+		},
+		"cancel async siblings on failure": {
+			taskName: "foo",
+			err:      "async job 2 (fail): fail",
+			m: manifest.Manifest{Tasks: manifest.TaskSet{"foo": manifest.Task{
+				OnAsyncFailure: manifest.AsyncFailureCancelSiblings,
+				Jobs: []manifest.Job{
+					{Description: "long", ActionName: "testCancel", Async: true},
+					{Description: "fail", ActionName: testAction, Params: manifest.ActionParams{"err": "fail"}, Async: true},
+				},
+			}}},
+			before: func(t *testing.T, _ *TaskRunner, hs *HandlerSet, r *results) {
+				_ = hs.HandleFunc("testCancel", func(*scope.Scope, manifest.ActionParams) (ActionHandler, error) {
+					return &asyncTestHandle{data: r}, nil
+				})
+			},
+		},
+		"run shared dependencies once": {
+			taskName: "release",
+			m: manifest.Manifest{
+				Tasks: manifest.TaskSet{
+					"release": manifest.Task{
+						Depends: []string{"build", "lint"},
+						Jobs:    []manifest.Job{{ActionName: "testRecord", Params: manifest.ActionParams{"name": "release"}}},
+					},
+					"build": manifest.Task{
+						Depends: []string{"generate"},
+						Jobs:    []manifest.Job{{ActionName: "testRecord", Params: manifest.ActionParams{"name": "build"}}},
+					},
+					"lint": manifest.Task{
+						Depends: []string{"generate"},
+						Jobs:    []manifest.Job{{ActionName: "testRecord", Params: manifest.ActionParams{"name": "lint"}}},
+					},
+					"generate": manifest.Task{
+						Jobs: []manifest.Job{{ActionName: "testRecord", Params: manifest.ActionParams{"name": "generate"}}},
+					},
+				},
+			},
+			before: func(t *testing.T, _ *TaskRunner, hs *HandlerSet, r *results) {
+				_ = hs.HandleFunc("testRecord", newRecordAction(r))
+			},
+			after: func(t *testing.T, _ *TaskRunner, _ *test.Log, r *results) {
+				require.Len(t, r.calls, 4)
+				assert.Equal(t, "generate", r.calls[0])
+				assert.ElementsMatch(t, []string{"build", "lint"}, r.calls[1:3])
+				assert.Equal(t, "release", r.calls[3])
+			},
+		},
+		"run sub-task dependencies once": {
+			taskName: "foo",
+			m: manifest.Manifest{
+				Tasks: manifest.TaskSet{
+					"foo": manifest.Task{
+						Depends: []string{"generate"},
+						Jobs:    []manifest.Job{{TaskName: "bar"}},
 					},
 					"bar": manifest.Task{
-						manifest.Job{ActionName: testAction, Params: manifest.ActionParams{"err": "fail"}},
+						Depends: []string{"generate"},
+						Jobs:    []manifest.Job{{ActionName: "testRecord", Params: manifest.ActionParams{"name": "bar"}}},
+					},
+					"generate": manifest.Task{
+						Jobs: []manifest.Job{{ActionName: "testRecord", Params: manifest.ActionParams{"name": "generate"}}},
+					},
+				},
+			},
+			before: func(t *testing.T, _ *TaskRunner, hs *HandlerSet, r *results) {
+				_ = hs.HandleFunc("testRecord", newRecordAction(r))
+			},
+			after: func(t *testing.T, _ *TaskRunner, _ *test.Log, r *results) {
+				assert.Equal(t, []string{"generate", "bar"}, r.calls)
+			},
+		},
+		"run dependencies on each invocation": {
+			taskName: "foo",
+			m: manifest.Manifest{
+				Tasks: manifest.TaskSet{
+					"foo": manifest.Task{
+						Depends: []string{"generate"},
+						Jobs:    []manifest.Job{{TaskName: "bar"}},
+					},
+					"bar": manifest.Task{
+						Depends: []string{"generate"},
+						Jobs:    []manifest.Job{{ActionName: "testRecord", Params: manifest.ActionParams{"name": "bar"}}},
+					},
+					"generate": manifest.Task{
+						Jobs: []manifest.Job{{ActionName: "testRecord", Params: manifest.ActionParams{"name": "generate"}}},
 					},
 				},
 			},
+			before: func(t *testing.T, _ *TaskRunner, hs *HandlerSet, r *results) {
+				_ = hs.HandleFunc("testRecord", newRecordAction(r))
+			},
+			after: func(t *testing.T, tr *TaskRunner, _ *test.Log, r *results) {
+				require.NoError(t, tr.Run("foo", manifest.Vars{"foo": "bar"}))
+				assert.Equal(t, []string{"generate", "bar", "generate", "bar"}, r.calls)
+			},
+		},
+		"return dependency errors": {
+			taskName: "foo",
+			err:      `task "bar" returned an error on step 1: fail`,
+			m: manifest.Manifest{
+				Tasks: manifest.TaskSet{
+					"foo": manifest.Task{
+						Depends: []string{"bar"},
+						Jobs:    []manifest.Job{{ActionName: testAction}},
+					},
+					"bar": manifest.Task{
+						Jobs: []manifest.Job{{ActionName: testAction, Params: manifest.ActionParams{"err": "fail"}}},
+					},
+				},
+			},
+		},
+		"limit parallel actions": {
+			taskName:    "foo",
+			maxParallel: 2,
+			m: manifest.Manifest{
+				Mixins: manifest.Mixins{
+					"build": manifest.Mixin{
+						{ActionName: "testRecord", Params: manifest.ActionParams{"name": "build"}},
+						{ActionName: "testRecord", Params: manifest.ActionParams{"name": "build"}, Async: true},
+						{ActionName: "testRecord", Params: manifest.ActionParams{"name": "build"}, Async: true},
+					},
+				},
+				Tasks: manifest.TaskSet{
+					"foo": manifest.Task{Jobs: []manifest.Job{
+						{MixinName: "build", Vars: manifest.Vars{"name": "a"}, Async: true},
+						{MixinName: "build", Vars: manifest.Vars{"name": "b"}, Async: true},
+						{MixinName: "build", Vars: manifest.Vars{"name": "c"}, Async: true},
+						{TaskName: "bar", Async: true},
+						{ActionName: "testRecord", Params: manifest.ActionParams{"name": "foo"}},
+					}},
+					"bar": manifest.Task{Jobs: []manifest.Job{
+						{ActionName: "testRecord", Params: manifest.ActionParams{"name": "bar"}, Async: true},
+						{ActionName: "testRecord", Params: manifest.ActionParams{"name": "bar"}, Async: true},
+					}},
+				},
+			},
+			before: func(t *testing.T, _ *TaskRunner, hs *HandlerSet, r *results) {
+				_ = hs.HandleFunc("testRecord", newRecordAction(r))
+			},
+			after: func(t *testing.T, _ *TaskRunner, _ *test.Log, r *results) {
+				assert.Len(t, r.calls, 12)
+				assert.Equal(t, 2, r.maxActive)
+			},
+		},
+		"limit parallel async jobs of task": {
+			taskName: "foo",
+			m: manifest.Manifest{
+				Tasks: manifest.TaskSet{
+					"foo": manifest.Task{
+						MaxParallel: 1,
+						Jobs: []manifest.Job{
+							{ActionName: "testRecord", Params: manifest.ActionParams{"name": "a"}, Async: true},
+							{ActionName: "testRecord", Params: manifest.ActionParams{"name": "b"}, Async: true},
+							{ActionName: "testRecord", Params: manifest.ActionParams{"name": "c"}, Async: true},
+						},
+					},
+				},
+			},
+			before: func(t *testing.T, _ *TaskRunner, hs *HandlerSet, r *results) {
+				_ = hs.HandleFunc("testRecord", newRecordAction(r))
+			},
+			after: func(t *testing.T, _ *TaskRunner, _ *test.Log, r *results) {
+				assert.Equal(t, []string{"a", "b", "c"}, r.calls)
+				assert.Equal(t, 1, r.maxActive)
+			},
+		},
+		"skip job if inputs are unchanged": {
+			taskName: "foo",
+			m: manifest.Manifest{
+				Tasks: manifest.TaskSet{
+					"foo": manifest.Task{Jobs: []manifest.Job{
+						{ActionName: "testRecord", Params: manifest.ActionParams{"name": "a"}, Inputs: []string{"*.go"}},
+						{ActionName: "testRecord", Params: manifest.ActionParams{"name": "a"}, Inputs: []string{"*.go"}},
+						{ActionName: "testRecord", Params: manifest.ActionParams{"name": "b"}, Inputs: []string{"*.go"}},
+					}},
+				},
+			},
+			before: func(t *testing.T, tr *TaskRunner, hs *HandlerSet, r *results) {
+				tr.CurrentDirectory = t.TempDir()
+				require.NoError(t, os.WriteFile(filepath.Join(tr.CurrentDirectory, "main.go"), []byte("package main"), 0644))
+				_ = hs.HandleFunc("testRecord", newRecordAction(r))
+			},
+			after: func(t *testing.T, _ *TaskRunner, l *test.Log, r *results) {
+				assert.Equal(t, []string{"a", "b"}, r.calls)
+				l.AssertMessage("step was skipped, inputs and outputs are unchanged")
+			},
+		},
+		"run job without cache if fingerprint fails": {
+			taskName: "foo",
+			m: manifest.Manifest{
+				Vars: manifest.Vars{"broken": "$(exit 1)"},
+				Tasks: manifest.TaskSet{
+					"foo": manifest.Task{Jobs: []manifest.Job{
+						{ActionName: "testRecord", Params: manifest.ActionParams{"name": "a"}, Inputs: []string{"*.go"}},
+						{ActionName: "testRecord", Params: manifest.ActionParams{"name": "b"}, Inputs: []string{"[-]"}},
+					}},
+				},
+			},
+			before: func(t *testing.T, tr *TaskRunner, hs *HandlerSet, r *results) {
+				tr.CurrentDirectory = t.TempDir()
+				_ = hs.HandleFunc("testRecord", newRecordAction(r))
+			},
+			after: func(t *testing.T, _ *TaskRunner, l *test.Log, r *results) {
+				assert.Equal(t, []string{"a", "b"}, r.calls, "unused broken variable should be ignored")
+				l.AssertMessage("job will be started without cache")
+			},
+		},
+		"expand job matrix": {
+			taskName: "foo",
+			m: manifest.Manifest{
+				Tasks: manifest.TaskSet{
+					"foo": manifest.Task{Jobs: []manifest.Job{
+						{
+							ActionName: "testRecord",
+							Params:     manifest.ActionParams{"name": "${os}/${arch}"},
+							Async:      true,
+							Matrix: &manifest.Matrix{
+								Vars: []manifest.MatrixVar{
+									{Name: "os", Values: []string{"linux", "windows"}},
+									{Name: "arch", Values: []string{"386", "amd64"}},
+								},
+								Exclude: []manifest.Vars{{"os": "windows", "arch": "386"}},
+							},
+						},
+					}},
+				},
+			},
+			before: func(t *testing.T, _ *TaskRunner, hs *HandlerSet, r *results) {
+				_ = hs.HandleFunc("testRecord", newRecordAction(r))
+			},
+			after: func(t *testing.T, _ *TaskRunner, l *test.Log, r *results) {
+				assert.ElementsMatch(t, []string{"linux/386", "linux/amd64", "windows/amd64"}, r.calls)
+				assert.Equal(t, 3, r.maxActive, "matrix jobs should run in parallel")
+				l.AssertMessage("- [3/3] testRecord (os: windows, arch: amd64)")
+			},
+		},
+		"report variable cycle": {
+			taskName: "foo",
+			err:      "variable cycle: a -> b -> a\n  - b: defined in job \"expand vars\"",
+			m: manifest.Manifest{
+				Vars: manifest.Vars{"a": "${b}"},
+				Tasks: manifest.TaskSet{"foo": manifest.Task{Jobs: []manifest.Job{
+					manifest.Job{ActionName: "testExpand", Description: "expand vars", Vars: manifest.Vars{"b": "${a}"}},
+				}}},
+			},
+			before: func(t *testing.T, _ *TaskRunner, hs *HandlerSet, r *results) {
+				_ = hs.HandleFunc("testExpand", func(s *scope.Scope, _ manifest.ActionParams) (ActionHandler, error) {
+					_, err := s.ExpandVariables("${a}")
+					return nil, err
+				})
+			},
+		},
+		"report sub-task dependency cycle": {
+			taskName: "release",
+			err:      "task dependency cycle: release -> build -> release",
+			m: manifest.Manifest{
+				Tasks: manifest.TaskSet{
+					"release": manifest.Task{Depends: []string{"build"}},
+					"build":   manifest.Task{Jobs: []manifest.Job{{TaskName: "release"}}},
+				},
+			},
+		},
+		"report dependency cycle": {
+			taskName: "foo",
+			err:      "task dependency cycle: foo -> bar -> foo",
+			m: manifest.Manifest{
+				Tasks: manifest.TaskSet{
+					"foo": manifest.Task{Depends: []string{"bar"}},
+					"bar": manifest.Task{Depends: []string{"foo"}},
+				},
+			},
 		},
 	}
 
@@ -310,6 +664,8 @@ func TestTaskRunner_Run(t *testing.T) {
 				Logger:   l,
 				Handlers: handlers,
 				Manifest: &tc.m,
+
+				MaxParallel: tc.maxParallel,
 			})
 			r.startTime = time.Now()
 			if c.before != nil {
@@ -330,6 +686,73 @@ func TestTaskRunner_Run(t *testing.T) {
 	}
 }
 
+func TestTaskRunner_RestoreOutputs(t *testing.T) {
+	outputsCache := cache.New(cache.NewStore(t.TempDir()), nil)
+	m := manifest.Manifest{
+		Parser: expr.SpecV2Parser{},
+		Tasks: manifest.TaskSet{
+			"build": manifest.Task{Jobs: []manifest.Job{{
+				ActionName: "testRecord",
+				Params:     manifest.ActionParams{"name": "build", "output": "${PROJECT}/app"},
+				Inputs:     []string{"*.go"},
+				Outputs:    []string{"app"},
+			}}},
+		},
+	}
+
+	// run the same task in two different project copies
+	r := &results{}
+	for i := 0; i < 2; i++ {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0644))
+
+		l := &test.Log{T: t}
+		handlers := NewHandlerSet(ActionHandlers{"testRecord": newRecordAction(r)})
+		tr := NewTaskRunner(Config{
+			Logger:   l,
+			Handlers: handlers,
+			Manifest: &m,
+			WorkDir:  dir,
+			Cache:    outputsCache,
+		})
+		require.NoError(t, tr.Run("build", nil))
+
+		data, err := os.ReadFile(filepath.Join(dir, "app"))
+		require.NoError(t, err)
+		require.Equal(t, "build", string(data))
+	}
+
+	require.Equal(t, []string{"build"}, r.calls, "second job should be restored from cache")
+}
+
+func TestTaskRunner_CommandCache(t *testing.T) {
+	counter := filepath.Join(t.TempDir(), "counter")
+	m := manifest.Manifest{
+		Parser: expr.SpecV2Parser{},
+		Vars: manifest.Vars{
+			"count": "$(echo x >> " + counter + " && wc -l < " + counter + " | tr -d ' ')",
+		},
+		Tasks: manifest.TaskSet{
+			"build": manifest.Task{Jobs: []manifest.Job{
+				{ActionName: "testRecord", Params: manifest.ActionParams{"name": "${count}"}},
+				{ActionName: "testRecord", Params: manifest.ActionParams{"name": "${count}"}},
+			}},
+		},
+	}
+
+	r := &results{}
+	tr := NewTaskRunner(Config{
+		Logger:   &test.Log{T: t},
+		Handlers: NewHandlerSet(ActionHandlers{"testRecord": newRecordAction(r)}),
+		Manifest: &m,
+	})
+
+	// command is evaluated once per invocation
+	require.NoError(t, tr.Run("build", nil))
+	require.NoError(t, tr.Run("build", nil))
+	require.Equal(t, []string{"1", "1", "2", "2"}, r.calls)
+}
+
 ///////////////////
 // Test Fixtures //
 ///////////////////
@@ -380,3 +803,37 @@ func (t *testActionHandler) Call(_ *job.RunContext, _ *TaskRunner) error {
 func (t *testActionHandler) Cancel(_ *job.RunContext) error {
 	return nil
 }
+
+func newRecordAction(r *results) HandlerFactory {
+	return func(s *scope.Scope, p manifest.ActionParams) (ActionHandler, error) {
+		ac := &recordActionHandler{data: r}
+		if err := p.Unmarshal(ac); err != nil {
+			return nil, err
+		}
+
+		return ac, s.Scan(&ac.Name, &ac.Output)
+	}
+}
+
+// recordActionHandler saves call order
+type recordActionHandler struct {
+	Name   string `mapstructure:"name"`
+	Output string `mapstructure:"output"`
+	data   *results
+}
+
+func (t *recordActionHandler) Call(_ *job.RunContext, _ *TaskRunner) error {
+	t.data.begin()
+	defer t.data.end()
+	time.Sleep(time.Millisecond * 50)
+	t.data.addCall(t.Name)
+	if t.Output != "" {
+		return os.WriteFile(t.Output, []byte(t.Name), 0644)
+	}
+
+	return nil
+}
+
+func (t *recordActionHandler) Cancel(_ *job.RunContext) error {
+	return nil
+}
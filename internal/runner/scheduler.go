@@ -0,0 +1,49 @@
+package runner
+
+import (
+	"sync"
+
+	"github.com/go-gilbert/gilbert/internal/runner/job"
+)
+
+// scheduler limits count of actions running at the same time.
+//
+// Limit is shared between all tasks, sub-tasks and mixins started by task runner.
+type scheduler struct {
+	slots chan struct{}
+}
+
+// newScheduler creates a new scheduler with specified slots count.
+//
+// Zero or negative size means no limit.
+func newScheduler(size int) *scheduler {
+	if size <= 0 {
+		return &scheduler{}
+	}
+
+	return &scheduler{slots: make(chan struct{}, size)}
+}
+
+// acquire waits for a free slot and returns a function to release it.
+//
+// Jobs started by an action that already holds a slot (like "watch")
+// are using parent's slot to avoid deadlock.
+func (s *scheduler) acquire(ctx *job.RunContext) (release func(), err error) {
+	if s.slots == nil || ctx.HoldsSlot() {
+		return func() {}, nil
+	}
+
+	select {
+	case s.slots <- struct{}{}:
+	case <-ctx.Context().Done():
+		return nil, ctx.Context().Err()
+	}
+
+	ctx.HoldSlot()
+	once := &sync.Once{}
+	return func() {
+		once.Do(func() {
+			<-s.slots
+		})
+	}, nil
+}
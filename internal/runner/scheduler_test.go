@@ -0,0 +1,35 @@
+package runner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-gilbert/gilbert/internal/runner/job"
+	"github.com/go-gilbert/gilbert/internal/support/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_Acquire(t *testing.T) {
+	l := &test.Log{T: t}
+	s := newScheduler(1)
+
+	parent := job.NewRunContext(context.Background(), nil, l)
+	release, err := s.acquire(parent)
+	require.NoError(t, err)
+
+	// nested job should reuse parent's slot
+	_, err = s.acquire(parent.ChildContext())
+	require.NoError(t, err)
+
+	// other jobs should wait for a free slot
+	other := job.NewRunContext(context.Background(), nil, l)
+	other.Cancel()
+	_, err = s.acquire(other)
+	require.ErrorIs(t, err, context.Canceled)
+
+	release()
+	release()
+	other = job.NewRunContext(context.Background(), nil, l)
+	_, err = s.acquire(other)
+	require.NoError(t, err)
+}
@@ -0,0 +1,71 @@
+package scope
+
+import (
+	"bytes"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CommandCache contains results of shell expressions evaluated during a run.
+//
+// Results are stored by command text, working directory and process environment,
+// so each command is evaluated only once even if it's used by many jobs.
+type CommandCache struct {
+	mtx     sync.Mutex
+	entries map[commandKey]*commandResult
+}
+
+type commandKey struct {
+	cmd string
+	dir string
+	env string
+}
+
+type commandResult struct {
+	once sync.Once
+	data []byte
+	err  error
+}
+
+// NewCommandCache creates a new shell expressions cache
+func NewCommandCache() *CommandCache {
+	return &CommandCache{
+		entries: make(map[commandKey]*commandResult),
+	}
+}
+
+// eval returns cached command result or calls evalFn if command wasn't evaluated yet.
+//
+// Concurrent calls with the same command wait for the first one.
+func (c *CommandCache) eval(cmd string, proc *exec.Cmd, evalFn func() ([]byte, error)) ([]byte, error) {
+	key := newCommandKey(cmd, proc)
+
+	c.mtx.Lock()
+	res, ok := c.entries[key]
+	if !ok {
+		res = new(commandResult)
+		c.entries[key] = res
+	}
+	c.mtx.Unlock()
+
+	res.once.Do(func() {
+		res.data, res.err = evalFn()
+	})
+
+	return bytes.Clone(res.data), res.err
+}
+
+func newCommandKey(cmd string, proc *exec.Cmd) commandKey {
+	// environment is built from maps, so order of variables is not stable
+	env := make([]string, len(proc.Env))
+	copy(env, proc.Env)
+	sort.Strings(env)
+
+	return commandKey{
+		cmd: cmd,
+		dir: proc.Dir,
+		env: strings.Join(env, "\x00"),
+	}
+}
@@ -0,0 +1,79 @@
+package scope
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-gilbert/gilbert/internal/manifest"
+	"github.com/go-gilbert/gilbert/internal/manifest/expr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScope_UseCommandCache(t *testing.T) {
+	const input = "$(echo call >> calls.txt && echo ${name})"
+	cases := map[string]struct {
+		cache       bool
+		names       []string
+		expectCalls int
+	}{
+		"evaluate command once": {
+			cache:       true,
+			names:       []string{"foo", "foo", "foo"},
+			expectCalls: 1,
+		},
+		"evaluate command with different env": {
+			cache:       true,
+			names:       []string{"foo", "bar", "foo"},
+			expectCalls: 2,
+		},
+		"evaluate command each time without cache": {
+			names:       []string{"foo", "foo", "foo"},
+			expectCalls: 3,
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			dir := t.TempDir()
+			var cache *CommandCache
+			if c.cache {
+				cache = NewCommandCache()
+			}
+
+			for _, name := range c.names {
+				s := CreateScope(expr.SpecV2Parser{}, dir, manifest.Vars{"name": name}).UseCommandCache(cache)
+				got, err := s.ExpandVariables(input)
+				require.NoError(t, err)
+				require.Equal(t, name, strings.TrimSpace(got))
+			}
+
+			data, err := os.ReadFile(filepath.Join(dir, "calls.txt"))
+			require.NoError(t, err)
+			require.Equal(t, c.expectCalls, strings.Count(string(data), "call"))
+		})
+	}
+}
+
+func TestCommandCache_Concurrent(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewCommandCache()
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s := CreateScope(expr.SpecV2Parser{}, dir, nil).UseCommandCache(cache)
+			_, err := s.ExpandVariables("$(echo call >> calls.txt)")
+			require.NoError(t, err)
+		}()
+	}
+
+	wg.Wait()
+	data, err := os.ReadFile(filepath.Join(dir, "calls.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "call\n", string(data))
+}
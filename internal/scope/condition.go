@@ -0,0 +1,127 @@
+package scope
+
+import (
+	"context"
+	"errors"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/go-gilbert/gilbert/internal/manifest/expr"
+	"github.com/go-gilbert/gilbert/internal/support/git"
+)
+
+// ciEnvVars are environment variables which presence indicates CI environment
+var ciEnvVars = []string{"CI", "GITHUB_ACTIONS", "GITLAB_CI", "BUILDKITE", "TF_BUILD", "JENKINS_URL", "TEAMCITY_VERSION"}
+
+var (
+	// currentBranch returns current git branch of directory
+	currentBranch = git.CurrentBranch
+
+	// branches contains cached git branch by project directory
+	branches sync.Map
+)
+
+// conditionEnv provides scope facts and variables to condition expressions
+type conditionEnv struct {
+	scopeExprAdapter
+	values map[string]any
+}
+
+func (e conditionEnv) Values() any {
+	return e.values
+}
+
+// EvalCondition evaluates job condition expression.
+//
+// Expression has access to the following values:
+//
+//	os     - operating system (runtime.GOOS)
+//	arch   - architecture (runtime.GOARCH)
+//	ci     - true if running in CI environment
+//	git    - git repository information (git.branch)
+//	vars   - resolved scope variables (numbers, lists and maps are typed)
+//
+// Environment variables are available using "env" function or "env.NAME" syntax.
+func (c *Scope) EvalCondition(condition string) (bool, error) {
+	if strings.TrimSpace(condition) == "" {
+		return false, errors.New("condition expression cannot be empty")
+	}
+
+	// resolve only used values, as variables and git info might require to run commands
+	refs, err := expr.ConditionReferences(condition)
+	if err != nil {
+		return false, err
+	}
+
+	names := refs.Vars
+	if refs.AllVars {
+		names = c.varNames()
+	}
+
+	resolved, err := c.resolveVars(names)
+	if err != nil {
+		return false, err
+	}
+
+	// variables have the same types as in "${...}" expressions
+	vars := make(map[string]any, len(resolved))
+	for k, v := range resolved {
+		vars[k] = expr.TypedValue(v)
+	}
+
+	gitInfo := map[string]string{}
+	if slices.Contains(refs.Names, "git") {
+		gitInfo["branch"] = c.gitBranch()
+	}
+
+	env := conditionEnv{
+		scopeExprAdapter: newScopeExprAdapter(c),
+		values: map[string]any{
+			"os":   runtime.GOOS,
+			"arch": runtime.GOARCH,
+			"ci":   isCI(),
+			"git":  gitInfo,
+			"vars": vars,
+		},
+	}
+
+	return expr.EvalCondition(expr.EvalContext{
+		CommandProcessor: env,
+		Env:              env,
+		WorkDir:          c.environment.ProjectDirectory,
+	}, condition)
+}
+
+// gitBranch returns current git branch of project directory.
+//
+// Branch is resolved only once per directory, empty string is returned if it's not a git repository.
+func (c *Scope) gitBranch() string {
+	dir := c.environment.ProjectDirectory
+	if v, ok := branches.Load(dir); ok {
+		return v.(string)
+	}
+
+	branch, err := currentBranch(context.Background(), dir)
+	if err != nil {
+		branch = ""
+	}
+
+	branches.Store(dir, branch)
+	return branch
+}
+
+func isCI() bool {
+	for _, name := range ciEnvVars {
+		switch strings.ToLower(os.Getenv(name)) {
+		case "", "0", "false":
+			continue
+		default:
+			return true
+		}
+	}
+
+	return false
+}
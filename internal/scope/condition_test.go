@@ -0,0 +1,132 @@
+package scope
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/go-gilbert/gilbert/internal/manifest"
+	"github.com/go-gilbert/gilbert/internal/manifest/expr"
+	"github.com/go-gilbert/gilbert/internal/support/git"
+	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScope_EvalCondition(t *testing.T) {
+	t.Setenv("CI", "true")
+	t.Setenv("GILBERT_TEST_VAR", "foo")
+
+	dir := t.TempDir()
+	calls := 0
+	currentBranch = func(_ context.Context, d string) (string, error) {
+		calls++
+		assert.Equal(t, dir, d)
+		return "main", nil
+	}
+	t.Cleanup(func() {
+		currentBranch = git.CurrentBranch
+	})
+
+	s := CreateScope(expr.SpecV2Parser{}, dir, manifest.Vars{
+		"release": "true",
+		"target":  "${GOOS}-amd64",
+		"broken":  "$(exit 1)",
+		"retries": "3",
+	}).AppendGlobals(manifest.Vars{"GOOS": "linux"})
+
+	cases := map[string]struct {
+		cond string
+		want bool
+		err  string
+	}{
+		"facts": {
+			cond: `os == "` + runtime.GOOS + `" && arch == "` + runtime.GOARCH + `" && ci`,
+			want: true,
+		},
+		"environment variables": {
+			cond: `env.GILBERT_TEST_VAR == "foo"`,
+			want: true,
+		},
+		"git branch": {
+			cond: `git.branch in ["main", "master"]`,
+			want: true,
+		},
+		"resolved variables": {
+			cond: `vars.release == "true" && vars.target == "linux-amd64" && vars.GOOS == "linux"`,
+			want: true,
+		},
+		"typed variables": {
+			cond: `vars.retries > 2 && vars.retries + 1 == 4`,
+			want: true,
+		},
+		"resolve only referenced variables": {
+			cond: `vars["release"] == "true"`,
+			want: true,
+		},
+		"resolve all variables": {
+			cond: `len(vars) > 0`,
+			err:  `failed to resolve variable "broken"`,
+		},
+		"false condition": {
+			cond: `vars.release == "false"`,
+		},
+		"empty condition": {
+			cond: " ",
+			err:  "condition expression cannot be empty",
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			got, err := s.EvalCondition(c.cond)
+			if c.err != "" {
+				assert.ErrorContains(t, err, c.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, c.want, got)
+		})
+	}
+
+	// git branch is resolved once per project directory
+	assert.Equal(t, 1, calls)
+}
+
+func TestScope_EvalCondition_ListVars(t *testing.T) {
+	var m manifest.Manifest
+	src := "vars:\n  targets: [linux, darwin]\n  limits:\n    linux: 2\n"
+	require.NoError(t, yaml.Unmarshal([]byte(src), &m))
+
+	s := CreateScope(expr.NewSpecV3Parser(), t.TempDir(), m.Vars)
+	for _, cond := range []string{
+		`"darwin" in vars.targets`,
+		`len(vars.targets) == 2 && vars.targets[0] == "linux"`,
+		`vars.limits.linux > 1`,
+	} {
+		ok, err := s.EvalCondition(cond)
+		require.NoError(t, err, cond)
+		assert.True(t, ok, cond)
+	}
+
+	// the same value is available in expressions
+	out, err := s.ExpandVariables("${ len(targets) } ${ targets[1] }")
+	require.NoError(t, err)
+	assert.Equal(t, "2 darwin", out)
+}
+
+func TestScope_GitBranch_NotRepository(t *testing.T) {
+	currentBranch = func(context.Context, string) (string, error) {
+		return "", errors.New("not a git repository")
+	}
+	t.Cleanup(func() {
+		currentBranch = git.CurrentBranch
+	})
+
+	s := CreateScope(expr.SpecV2Parser{}, t.TempDir(), nil)
+	ok, err := s.EvalCondition(`git.branch == ""`)
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
@@ -2,6 +2,7 @@ package scope
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 
@@ -18,6 +19,10 @@ type Scope struct {
 	Variables   manifest.Vars
 	parser      expr.Parser
 	environment ProjectEnvironment
+	commands    *CommandCache
+
+	// sources contains descriptions of places where variables were defined
+	sources map[string]string
 }
 
 // CreateScope creates a new context
@@ -58,6 +63,35 @@ func (c *Scope) AppendVariables(vars manifest.Vars) *Scope {
 	return c
 }
 
+// UseCommandCache sets cache of shell expression results shared between scopes.
+//
+// Commands are evaluated each time if cache is nil.
+func (c *Scope) UseCommandCache(cache *CommandCache) *Scope {
+	c.commands = cache
+	return c
+}
+
+// AddVarSources adds descriptions of places where variables were defined (e.g. manifest file or job).
+//
+// Sources are used in variable resolution errors.
+func (c *Scope) AddVarSources(sources map[string]string) *Scope {
+	if c.sources == nil {
+		c.sources = make(map[string]string, len(sources))
+	}
+
+	for k, v := range sources {
+		c.sources[k] = v
+	}
+
+	return c
+}
+
+// VarSource returns description of place where variable was defined
+func (c *Scope) VarSource(varName string) (string, bool) {
+	src, ok := c.sources[varName]
+	return src, ok
+}
+
 // Global returns a global variable value by it's name
 func (c *Scope) Global(varName string) (out string, ok bool) {
 	out, ok = c.Globals[varName]
@@ -116,3 +150,96 @@ func (c *Scope) Environ() (env []string) {
 
 	return
 }
+
+// ResolveVars returns all global and local variables with evaluated values.
+//
+// Local variables override globals with the same name.
+func (c *Scope) ResolveVars() (manifest.Vars, error) {
+	return c.resolveVars(c.varNames())
+}
+
+// varNames returns names of global and local variables
+func (c *Scope) varNames() []string {
+	names := make([]string, 0, len(c.Globals)+len(c.Variables))
+	for k := range c.Globals.Append(c.Variables) {
+		names = append(names, k)
+	}
+
+	return names
+}
+
+// resolveVars returns evaluated values of variables with specified names.
+//
+// Undefined variables are ignored.
+func (c *Scope) resolveVars(names []string) (manifest.Vars, error) {
+	out := make(manifest.Vars, len(names))
+	for _, k := range names {
+		_, v, ok := c.Var(k)
+		if !ok {
+			continue
+		}
+
+		if c.parser == nil || !c.parser.ContainsExpression(v) {
+			out[k] = v
+			continue
+		}
+
+		val, err := c.ExpandVariables(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve variable %q: %w", k, err)
+		}
+
+		out[k] = val
+	}
+
+	return out, nil
+}
+
+// ExpandParams returns a copy of action params with expanded expressions in all string values.
+//
+// Nested lists and maps are processed recursively.
+func (c *Scope) ExpandParams(params manifest.ActionParams) (manifest.ActionParams, error) {
+	if params == nil {
+		return nil, nil
+	}
+
+	out, err := c.expandValue(map[string]interface{}(params))
+	if err != nil {
+		return nil, err
+	}
+
+	return out.(map[string]interface{}), nil
+}
+
+func (c *Scope) expandValue(v interface{}) (interface{}, error) {
+	switch t := v.(type) {
+	case string:
+		return c.ExpandVariables(t)
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, item := range t {
+			val, err := c.expandValue(item)
+			if err != nil {
+				return nil, err
+			}
+
+			out[i] = val
+		}
+
+		return out, nil
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, item := range t {
+			val, err := c.expandValue(item)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", k, err)
+			}
+
+			out[k] = val
+		}
+
+		return out, nil
+	default:
+		return v, nil
+	}
+}
@@ -2,6 +2,8 @@ package scope
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -96,3 +98,101 @@ func TestVarsExtract(t *testing.T) {
 	}
 
 }
+
+func TestScope_ResolveVars(t *testing.T) {
+	c := CreateScope(expr.SpecV2Parser{}, "/project", manifest.Vars{"foo": "${bar}-local"}).
+		AppendGlobals(manifest.Vars{"bar": "global", "foo": "global"})
+
+	vars, err := c.ResolveVars()
+	require.NoError(t, err)
+	assert.Equal(t, "global-local", vars["foo"])
+	assert.Equal(t, "global", vars["bar"])
+	assert.Equal(t, "/project", vars["PROJECT"])
+
+	c.AppendVariables(manifest.Vars{"bad": "${undefined}"})
+	_, err = c.ResolveVars()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `failed to resolve variable "bad"`)
+}
+
+func TestScope_ExpandVariables_SpecV3(t *testing.T) {
+	dir := t.TempDir()
+	c := CreateScope(expr.NewSpecV3Parser(), dir, manifest.Vars{
+		"name":   "gilbert",
+		"binary": "${ PROJECT }/build/${ name + ext }",
+	}).AppendGlobals(manifest.Vars{"ext": ".exe"})
+
+	out, err := c.ExpandVariables("${ binary } ${ upper(vars.name) } $$HOME $(echo ${ext})")
+	require.NoError(t, err)
+	assert.Equal(t, dir+"/build/gilbert.exe GILBERT $HOME .exe", out)
+}
+
+func TestScope_ExpandVariables_ProjectDirectory(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "VERSION"), []byte("1.0.0\n"), 0644))
+
+	c := CreateScope(expr.NewSpecV3Parser(), dir, nil)
+	out, err := c.ExpandVariables(`${ file("VERSION") } ${ glob("*") }`)
+	require.NoError(t, err)
+	assert.Equal(t, `1.0.0 ["VERSION"]`, out)
+
+	ok, err := c.EvalCondition(`file("VERSION") == "1.0.0"`)
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestScope_ExpandVariables_VarCycle(t *testing.T) {
+	const expectErr = "variable cycle: a -> b -> c -> a\n" +
+		"  - a: defined in manifest file \"gilbert.yaml\"\n" +
+		"  - b: defined in job \"build\"\n" +
+		"  - c: defined in --var flag"
+
+	parsers := map[string]expr.Parser{
+		"spec v2": expr.SpecV2Parser{},
+		"spec v3": expr.NewSpecV3Parser(),
+	}
+
+	for n, parser := range parsers {
+		t.Run(n, func(t *testing.T) {
+			c := CreateScope(parser, t.TempDir(), manifest.Vars{"b": "${c}", "c": "${a}"}).
+				AppendGlobals(manifest.Vars{"a": "${b}"}).
+				AddVarSources(map[string]string{
+					"a": `manifest file "gilbert.yaml"`,
+					"b": `job "build"`,
+					"c": "--var flag",
+				})
+
+			_, err := c.ExpandVariables("${a}")
+			require.Error(t, err)
+
+			var cycleErr *expr.VarCycleError
+			require.ErrorAs(t, err, &cycleErr)
+			require.Equal(t, []string{"a", "b", "c", "a"}, cycleErr.Chain)
+			require.EqualError(t, err, expectErr)
+		})
+	}
+}
+
+func TestScope_ExpandParams(t *testing.T) {
+	c := CreateScope(expr.SpecV2Parser{}, "/project", manifest.Vars{"os": "linux"})
+	params := manifest.ActionParams{
+		"output": "${PROJECT}/build",
+		"count":  1,
+		"target": map[string]interface{}{"os": "${os}"},
+		"tags":   []interface{}{"${os}", true},
+	}
+
+	got, err := c.ExpandParams(params)
+	require.NoError(t, err)
+	assert.Equal(t, manifest.ActionParams{
+		"output": "/project/build",
+		"count":  1,
+		"target": map[string]interface{}{"os": "linux"},
+		"tags":   []interface{}{"linux", true},
+	}, got)
+	assert.Equal(t, "${PROJECT}/build", params["output"], "source params should not be modified")
+
+	_, err = c.ExpandParams(manifest.ActionParams{"target": map[string]interface{}{"os": "${arch}"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "target:")
+}
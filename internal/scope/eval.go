@@ -11,6 +11,7 @@ import (
 var (
 	_ expr.CommandProcessor = (*scopeExprAdapter)(nil)
 	_ expr.ValueResolver    = (*scopeExprAdapter)(nil)
+	_ expr.SourceResolver   = (*scopeExprAdapter)(nil)
 )
 
 // scopeExprAdapter implements CommandProcessor and ValueResolver for expression parser to operate on a scope.
@@ -33,9 +34,21 @@ func (e scopeExprAdapter) prepareProcess(cmd string) (proc *exec.Cmd) {
 	return proc
 }
 
+// EvalCommand runs shell command and returns its output.
+//
+// Results are reused if scope has command cache.
 func (e scopeExprAdapter) EvalCommand(cmd string) (result []byte, err error) {
 	proc := e.prepareProcess(cmd)
+	if e.ctx.commands == nil {
+		return runCommand(proc)
+	}
+
+	return e.ctx.commands.eval(cmd, proc, func() ([]byte, error) {
+		return runCommand(proc)
+	})
+}
 
+func runCommand(proc *exec.Cmd) (result []byte, err error) {
 	data, err := proc.CombinedOutput()
 	if err != nil {
 		return result, fmt.Errorf("%w (%s)", shell.FormatExitError(err), data)
@@ -49,15 +62,22 @@ func (e scopeExprAdapter) ValueByName(varName string) (string, bool) {
 	return val, ok
 }
 
+func (e scopeExprAdapter) VarSource(varName string) (string, bool) {
+	return e.ctx.VarSource(varName)
+}
+
+// Values returns raw values of global and local variables.
+//
+// Local variables override globals with the same name.
 func (e scopeExprAdapter) Values() any {
-	// TODO: Will be replaced.
-	return e.ctx.Variables
+	return map[string]string(e.ctx.Globals.Append(e.ctx.Variables))
 }
 
 func (e scopeExprAdapter) evalContext() expr.EvalContext {
 	return expr.EvalContext{
 		CommandProcessor: e,
 		Env:              e,
+		WorkDir:          e.ctx.environment.ProjectDirectory,
 	}
 }
 
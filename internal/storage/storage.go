@@ -16,8 +16,6 @@ const (
 	StoreVarName = "GILBERT_HOME"
 )
 
-var storageDir string
-
 // Type represents storage type
 type Type int
 
@@ -27,22 +25,32 @@ const (
 
 	// Plugins represents plugins storage
 	Plugins
+
+	// Fingerprints represents storage of incremental jobs state
+	Fingerprints
+
+	// Cache represents job outputs cache storage
+	Cache
+
+	// Coverage represents code coverage history storage
+	Coverage
 )
 
 var storageTypes = map[Type]string{
-	Root:    "",
-	Plugins: "plugins",
+	Root:         "",
+	Plugins:      "plugins",
+	Fingerprints: "fingerprints",
+	Cache:        "cache",
+	Coverage:     "coverage",
 }
 
+// home returns storage root directory.
+//
+// Env variable is read on each call, so storage location can be changed at runtime.
 func home() (string, error) {
-	if storageDir != "" {
-		return storageDir, nil
-	}
-
 	// override storage directory by env variable if present
 	if envVal := os.Getenv(StoreVarName); envVal != "" {
-		storageDir = envVal
-		return storageDir, nil
+		return envVal, nil
 	}
 
 	home, err := os.UserHomeDir()
@@ -81,12 +89,17 @@ func LocalPath(storageType Type, paths ...string) (string, error) {
 		return "", err
 	}
 
+	return ProjectPath(wd, storageType, paths...)
+}
+
+// ProjectPath returns storage path by type for project in specified directory
+func ProjectPath(projectDir string, storageType Type, paths ...string) (string, error) {
 	dir, ok := storageTypes[storageType]
 	if !ok {
 		return "", errors.New("unknown storage type")
 	}
 
-	p := filepath.Join(wd, homeDirName, dir)
+	p := filepath.Join(projectDir, homeDirName, dir)
 
 	if len(paths) > 0 {
 		p += string(os.PathSeparator) + filepath.Join(paths...)
@@ -93,6 +93,15 @@ func TestLocalPath(t *testing.T) {
 	assert.Equal(t, filepath.Join(cwd, homeDirName, "foo"), val)
 }
 
+func TestProjectPath(t *testing.T) {
+	_, err := ProjectPath("project", Type(48))
+	assert.Error(t, err)
+
+	val, err := ProjectPath("project", Fingerprints, "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join("project", homeDirName, "fingerprints", "foo"), val)
+}
+
 func TestDelete(t *testing.T) {
 	t.Log(os.Unsetenv(StoreVarName))
 	t.Log(os.Setenv(StoreVarName, "testdata"))
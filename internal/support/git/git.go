@@ -0,0 +1,68 @@
+/*
+Package git contains helpers to query git repository information
+*/
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Output runs git command in specified directory and returns its stdout
+func Output(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+
+		return nil, fmt.Errorf("'git %s' failed: %s", strings.Join(args, " "), msg)
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// outputString runs git command and returns trimmed output
+func outputString(ctx context.Context, dir string, args ...string) (string, error) {
+	out, err := Output(ctx, dir, args...)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// TopLevel returns repository root directory
+func TopLevel(ctx context.Context, dir string) (string, error) {
+	return outputString(ctx, dir, "rev-parse", "--show-toplevel")
+}
+
+// MergeBase returns common ancestor commit of HEAD and specified revision
+func MergeBase(ctx context.Context, dir, rev string) (string, error) {
+	return outputString(ctx, dir, "merge-base", rev, "HEAD")
+}
+
+// HeadCommit returns hash of the current commit
+func HeadCommit(ctx context.Context, dir string) (string, error) {
+	return outputString(ctx, dir, "rev-parse", "HEAD")
+}
+
+// CurrentBranch returns name of the current branch.
+//
+// Returns empty string if HEAD is detached.
+func CurrentBranch(ctx context.Context, dir string) (string, error) {
+	branch, err := outputString(ctx, dir, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil || branch == "HEAD" {
+		return "", err
+	}
+
+	return branch, nil
+}